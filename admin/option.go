@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/charliego3/pallas/health"
+	"github.com/charliego3/pallas/httpx"
+	"github.com/charliego3/pallas/utility"
+	"google.golang.org/grpc"
+)
+
+// WithAddr specifies the TCP address for the admin server to listen on,
+// it should be an internal-only address, eg: 127.0.0.1:9090
+func WithAddr(network, addr string) utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		listener, err := net.Listen(network, addr)
+		if err != nil {
+			panic(err)
+		}
+		s.Listener = listener
+	})
+}
+
+// WithListener use this listener on admin server
+func WithListener(lis net.Listener) utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.Listener = lis
+	})
+}
+
+// WithRoutes specifies the route walker, eg: httpx.Server.Walk
+func WithRoutes(walk func(httpx.RouteWalkFunc) error) utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.routes = walk
+	})
+}
+
+// WithServices specifies the registered grpc services, eg: grpcx.Server.GetServiceInfo
+func WithServices(services func() map[string]grpc.ServiceInfo) utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.services = services
+	})
+}
+
+// WithConfig specifies the effective config, the secrets should be masked
+func WithConfig(config func() (map[string]any, error)) utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.config = config
+	})
+}
+
+// WithHealth serves the liveness and readiness probes
+func WithHealth(h *health.Health) utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.mux.Handle(health.LivezURI, h.LivezHandler())
+		s.mux.Handle(health.ReadyzURI, h.ReadyzHandler())
+	})
+}
+
+// WithHandler mount the custom handler on admin server
+func WithHandler(pattern string, handler http.Handler) utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.mux.Handle(pattern, handler)
+	})
+}
+
+func WithLogger(logger *slog.Logger) utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.Logger = logger
+	})
+}
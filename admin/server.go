@@ -0,0 +1,169 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"runtime/debug"
+	"sort"
+
+	"github.com/charliego3/pallas/configx"
+	"github.com/charliego3/pallas/httpx"
+	"github.com/charliego3/pallas/types"
+	"github.com/charliego3/pallas/utility"
+	"google.golang.org/grpc"
+)
+
+const (
+	RoutesURI    = "/debug/routes"
+	ServicesURI  = "/debug/services"
+	ConfigURI    = "/debug/config"
+	BuildInfoURI = "/debug/buildinfo"
+)
+
+var _ types.Component = (*Server)(nil)
+
+// Server is the internal-only admin server which serves pprof, expvar,
+// route table, grpc services, masked config, build info and health
+type Server struct {
+	*types.BaseServer
+	*http.Server
+	mux *http.ServeMux
+
+	routes   func(httpx.RouteWalkFunc) error
+	services func() map[string]grpc.ServiceInfo
+	config   func() (map[string]any, error)
+}
+
+// NewServer returns admin server, the listener must be specified
+func NewServer(opts ...utility.Option[Server]) *Server {
+	s := new(Server)
+	s.BaseServer = types.NewBaseServer()
+	s.mux = http.NewServeMux()
+	s.Server = &http.Server{Handler: s.mux}
+	s.config = configx.Masked
+	s.mux.HandleFunc("/debug/pprof/", pprof.Index)
+	s.mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	s.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	s.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	s.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	s.mux.Handle("/debug/vars", expvar.Handler())
+	s.mux.HandleFunc(RoutesURI, s.handleRoutes)
+	s.mux.HandleFunc(ServicesURI, s.handleServices)
+	s.mux.HandleFunc(ConfigURI, s.handleConfig)
+	s.mux.HandleFunc(BuildInfoURI, s.handleBuildInfo)
+	utility.Apply(s, opts...)
+	return s
+}
+
+// Start serves the admin server until it's stopped
+func (s *Server) Start(context.Context) error {
+	if s.Listener == nil {
+		return errors.New("[Admin] not bind listener")
+	}
+
+	s.Logger.Info("[Admin] listening on", slog.String("address", s.Listener.Addr().String()))
+	if err := s.Serve(s.Listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Stop shutdown the admin server gracefully
+func (s *Server) Stop(ctx context.Context) error {
+	return s.Shutdown(ctx)
+}
+
+type route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+func (s *Server) handleRoutes(w http.ResponseWriter, _ *http.Request) {
+	routes := make([]route, 0)
+	if s.routes != nil {
+		if err := s.routes(func(method, path string) {
+			routes = append(routes, route{Method: method, Path: path})
+		}); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, routes)
+}
+
+type service struct {
+	Name     string   `json:"name"`
+	Methods  []string `json:"methods"`
+	Metadata any      `json:"metadata,omitempty"`
+}
+
+func (s *Server) handleServices(w http.ResponseWriter, _ *http.Request) {
+	services := make([]service, 0)
+	if s.services != nil {
+		for name, info := range s.services() {
+			methods := make([]string, 0, len(info.Methods))
+			for _, m := range info.Methods {
+				methods = append(methods, m.Name)
+			}
+			services = append(services, service{Name: name, Methods: methods, Metadata: info.Metadata})
+		}
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+	writeJSON(w, http.StatusOK, services)
+}
+
+func (s *Server) handleConfig(w http.ResponseWriter, _ *http.Request) {
+	config, err := s.config()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, config)
+}
+
+type buildInfo struct {
+	GoVersion string            `json:"goVersion"`
+	Path      string            `json:"path"`
+	Main      debug.Module      `json:"main"`
+	Deps      []*debug.Module   `json:"deps"`
+	Settings  map[string]string `json:"settings"`
+}
+
+func (s *Server) handleBuildInfo(w http.ResponseWriter, _ *http.Request) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		writeError(w, errors.New("build info is not available"))
+		return
+	}
+
+	settings := make(map[string]string, len(info.Settings))
+	for _, setting := range info.Settings {
+		settings[setting.Key] = setting.Value
+	}
+	writeJSON(w, http.StatusOK, buildInfo{
+		GoVersion: info.GoVersion,
+		Path:      info.Path,
+		Main:      info.Main,
+		Deps:      info.Deps,
+		Settings:  settings,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"err": err.Error()})
+}
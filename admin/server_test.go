@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charliego3/pallas/health"
+	"github.com/charliego3/pallas/httpx"
+)
+
+func TestServer(t *testing.T) {
+	h := health.New()
+	h.Resume()
+	s := NewServer(
+		WithRoutes(func(fn httpx.RouteWalkFunc) error {
+			fn(http.MethodGet, "/v1/books/{id}")
+			return nil
+		}),
+		WithConfig(func() (map[string]any, error) {
+			return map[string]any{"redis": map[string]any{"password": "******"}}, nil
+		}),
+		WithHealth(h),
+	)
+
+	tests := []struct {
+		uri    string
+		status int
+		body   string
+	}{
+		{RoutesURI, http.StatusOK, `"path": "/v1/books/{id}"`},
+		{ServicesURI, http.StatusOK, `[]`},
+		{ConfigURI, http.StatusOK, `"password": "******"`},
+		{health.LivezURI, http.StatusOK, `"status":"UP"`},
+		{health.ReadyzURI, http.StatusOK, `"status":"UP"`},
+		{"/debug/vars", http.StatusOK, "memstats"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		s.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.uri, nil))
+		if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("GET %s = %d %s, want %d %s", tt.uri, w.Code, w.Body, tt.status, tt.body)
+		}
+	}
+
+	h.Drain()
+	w := httptest.NewRecorder()
+	s.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, health.ReadyzURI, nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("readyz status = %d, want 503", w.Code)
+	}
+}
@@ -2,18 +2,31 @@ package pallas
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/charliego3/pallas/registry"
 	"golang.org/x/sync/errgroup"
 	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
 
 	"log/slog"
 
+	"github.com/charliego3/pallas/admin"
 	"github.com/charliego3/pallas/configx"
 
 	"github.com/charliego3/pallas/grpcx"
+	"github.com/charliego3/pallas/health"
 	"github.com/charliego3/pallas/httpx"
+	"github.com/charliego3/pallas/middleware/metrics"
 	"github.com/charliego3/pallas/types"
 	"github.com/charliego3/pallas/utility"
 	"github.com/soheilhy/cmux"
@@ -26,6 +39,13 @@ type Application struct {
 	// grpc server is grpcx.Server handler grpc request
 	grpc *grpcx.Server
 
+	// admin is the internal-only admin server, nil if disabled
+	admin *admin.Server
+
+	// health is ready after started and draining once shutting down
+	health  *health.Health
+	watcher *healthWatcher
+
 	// mux to accept http and grpc
 	// if http.Listener and grpc.Listener both nil will be using else is nil
 	mux cmux.CMux
@@ -33,11 +53,20 @@ type Application struct {
 	// Application config properties
 	*options
 
+	// lifecycle holds the start and stop hooks
+	lifecycle *lifecycle
+
+	// cancelComponents cancel the context of components
+	cancelComponents context.CancelFunc
+
+	mu       sync.Mutex
 	ctx      context.Context
 	stop     context.CancelFunc
 	waitC    chan struct{}
+	err      error
 	logger   *slog.Logger
 	registry registry.Registry
+	instance *registry.ServiceInstance
 }
 
 // NewApp returns Application
@@ -45,13 +74,30 @@ func NewApp(opts ...utility.Option[Application]) *Application {
 	app := new(Application)
 	app.logger = slog.Default()
 	app.options = new(options)
+	app.lifecycle = new(lifecycle)
+	app.health = health.New()
+	app.healthInterval = 5 * time.Second
 	app.grpcMatcher = cmux.HTTP2MatchHeaderFieldPrefixSendSettings("content-type", "application/grpc")
+	app.signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	app.shutdownTimeout = 30 * time.Second
 	utility.Apply(app, opts...)
 
+	cfg, _ := configx.Fetch[configx.App]()
+	app.name = utility.DString(app.name, utility.DString(cfg.Name, filepath.Base(os.Args[0])))
+	app.version = utility.DString(app.version, cfg.Version)
+	if utility.IsBlank(app.id) {
+		app.id = newID()
+	}
+
 	app.http = httpx.NewServer(app.hopts...)
-	app.grpc = grpcx.NewServer(app.gopts...)
+	// the grpc health status is updated by healthWatcher
+	app.grpc = grpcx.NewServer(append(app.gopts, grpcx.WithManagedHealth())...)
 	if utility.Nils(app.http.Listener, app.grpc.Listener) {
-		app.mux = cmux.New(app.getListener())
+		app.listener = app.getListener()
+		if app.metrics != nil {
+			app.listener = app.metrics.Listener(app.listener)
+		}
+		app.mux = cmux.New(app.listener)
 		app.grpc.Listener = app.mux.MatchWithWriters(app.grpcMatcher)
 		app.http.Listener = app.mux.Match(cmux.Any())
 	} else {
@@ -61,10 +107,56 @@ func NewApp(opts ...utility.Option[Application]) *Application {
 		}
 		app.grpc.Listener = utility.DObj(app.grpc.Listener, listener)
 		app.http.Listener = utility.DObj(app.http.Listener, listener)
+		if app.metrics != nil {
+			app.grpc.Listener = app.metrics.Listener(app.grpc.Listener)
+			app.http.Listener = app.metrics.Listener(app.http.Listener)
+		}
+	}
+	app.http.Mount(health.LivezURI, app.health.LivezHandler())
+	app.http.Mount(health.ReadyzURI, app.health.ReadyzHandler())
+	app.watcher = newHealthWatcher(app)
+	app.components = append(app.components, newSupervisor("health", app.watcher))
+	app.newAdmin(cfg)
+	if app.metrics != nil && app.admin == nil {
+		app.http.Mount(metrics.URI, app.metrics.Handler())
+	}
+	app.lifecycle.logger = app.logger
+	for _, c := range app.components {
+		c.logger = app.logger
 	}
 	return app
 }
 
+// newAdmin creates admin server if the admin address or options specified,
+// it's the first component so that it's stopped last
+func (app *Application) newAdmin(cfg configx.App) {
+	addr := utility.DString(app.adminAddr, cfg.Admin)
+	if utility.IsBlank(addr) && len(app.aopts) == 0 {
+		return
+	}
+
+	opts := []utility.Option[admin.Server]{
+		admin.WithLogger(app.logger),
+		admin.WithRoutes(app.http.Walk),
+		admin.WithServices(app.grpc.GetServiceInfo),
+		admin.WithHealth(app.health),
+	}
+	if utility.NonBlank(addr) {
+		opts = append(opts, admin.WithAddr("tcp", addr))
+	}
+	if app.metrics != nil {
+		opts = append(opts, admin.WithHandler(metrics.URI, app.metrics.Handler()))
+	}
+	app.admin = admin.NewServer(append(opts, app.aopts...)...)
+	app.components = append([]*Supervisor{newSupervisor("admin", app.admin)}, app.components...)
+}
+
+// Health returns the health checks of Application, the checks
+// drive the grpc health service and http /livez /readyz probes
+func (app *Application) Health() *health.Health {
+	return app.health
+}
+
 // getListener first using app options listener
 // otherwise using listener from config
 // last using a dynamic listener
@@ -88,6 +180,29 @@ func (app *Application) getListener() (listener net.Listener) {
 	return listener
 }
 
+// newID returns hostname with random suffix
+func newID() string {
+	hostname, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return utility.DString(hostname, "pallas") + "-" + hex.EncodeToString(suffix)
+}
+
+// ID returns service instance id
+func (app *Application) ID() string {
+	return app.id
+}
+
+// Name returns service name
+func (app *Application) Name() string {
+	return app.name
+}
+
+// Version returns service version
+func (app *Application) Version() string {
+	return app.version
+}
+
 // Address returns application listen address
 // this address is http and grpc both
 func (app *Application) Address() net.Addr {
@@ -103,51 +218,219 @@ func (app *Application) RegisterService(services ...types.Service) {
 	app.grpc.RegisterService(services...)
 }
 
-// Run start the server until terminate
+// Run start the server until terminate, it returns after
+// the servers are shutdown when any of the following happened:
+//
+//   - received the signals, default is SIGINT and SIGTERM
+//   - ctx is done or Shutdown is called
+//   - any server failed to serve
 func (app *Application) Run(ctx context.Context) (err error) {
+	app.mu.Lock()
+	if app.waitC != nil {
+		app.mu.Unlock()
+		return errors.New("[Application] already running")
+	}
 	app.ctx, app.stop = context.WithCancel(ctx)
-	if app.beforeStart != nil {
-		app.ctx, err = app.beforeStart(app.ctx)
-		if err != nil {
-			return err
-		}
+	app.waitC = make(chan struct{})
+	app.mu.Unlock()
+	defer func() {
+		app.err = err
+		app.stop()
+		close(app.waitC)
+	}()
+
+	if app.ctx, err = app.lifecycle.start(app.ctx); err != nil {
+		app.closeListeners()
+		return err
 	}
-	group, ctx := errgroup.WithContext(app.ctx)
+	group, gctx := errgroup.WithContext(app.ctx)
 	group.Go(func() error {
-		return app.http.Run(app.ctx)
+		return ignoreClosed(app.http.Run(gctx))
 	})
 	group.Go(func() error {
-		return app.grpc.Run(app.ctx)
+		return ignoreClosed(app.grpc.Run(gctx))
 	})
 	if app.mux != nil {
-		group.Go(app.mux.Serve)
+		group.Go(func() error {
+			return ignoreClosed(app.mux.Serve())
+		})
 	}
-	app.waitC = make(chan struct{})
-	defer close(app.waitC)
-	if app.afterStart != nil {
-		_, err = app.afterStart(app.ctx)
-		if err != nil {
-			return err
+
+	var cctx context.Context
+	cctx, app.cancelComponents = context.WithCancel(gctx)
+	for _, c := range app.components {
+		c := c
+		group.Go(func() error {
+			return c.Run(cctx)
+		})
+	}
+	if err = app.register(); err != nil {
+		return errors.Join(err, app.shutdown(), group.Wait())
+	}
+
+	app.health.Resume()
+	app.watcher.notify()
+	app.wait(gctx)
+	return errors.Join(app.shutdown(), group.Wait())
+}
+
+// wait blocks until ctx is done or received the signals
+func (app *Application) wait(ctx context.Context) {
+	if len(app.signals) == 0 {
+		<-ctx.Done()
+		return
+	}
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, app.signals...)
+	defer signal.Stop(c)
+	select {
+	case <-ctx.Done():
+	case sig := <-c:
+		app.logger.Info("[Application] received signal, shutting down", slog.String("signal", sig.String()))
+	}
+}
+
+// Shutdown stops the Application and waits Run to return,
+// it returns the error of Run, nil if the Application is not running
+func (app *Application) Shutdown() error {
+	app.mu.Lock()
+	waitC := app.waitC
+	app.mu.Unlock()
+	if waitC == nil {
+		return nil
+	}
+
+	app.stop()
+	<-waitC
+	return app.err
+}
+
+// shutdown drains the servers first so that no more requests
+// are routed to this instance, then stops the servers, cmux
+// listener, components and stop hooks in order within the shutdown timeout
+func (app *Application) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(app.ctx), app.shutdownTimeout)
+	defer cancel()
+
+	app.health.Drain()
+	app.grpc.Drain()
+	app.unregister()
+	if app.drainDelay > 0 {
+		select {
+		case <-ctx.Done():
+		case <-time.After(app.drainDelay):
 		}
 	}
-	return group.Wait()
+
+	errs := []error{app.http.Shutdown(ctx), app.grpc.Shutdown(ctx)}
+	if app.mux != nil {
+		app.mux.Close()
+		errs = append(errs, ignoreClosed(app.listener.Close()))
+	}
+
+	errs = append(errs, app.stopComponents(ctx))
+	app.cancelComponents()
+
+	errs = append(errs, app.lifecycle.stop(ctx))
+	return errors.Join(errs...)
 }
 
-func (app *Application) Shutdown() {
-	if app.beforeShutdown != nil {
-		err := app.beforeShutdown(app.ctx)
-		if err != nil {
-			return
+// closeListeners closes the listeners when the servers are not started
+func (app *Application) closeListeners() {
+	for _, lis := range []net.Listener{app.http.Listener, app.grpc.Listener, app.listener} {
+		if lis != nil {
+			_ = lis.Close()
 		}
 	}
+}
 
-	app.stop()
-	<-app.waitC
+// ignoreClosed returns nil if err is caused by server or listener closed
+func ignoreClosed(err error) error {
+	if errors.Is(err, http.ErrServerClosed) ||
+		errors.Is(err, cmux.ErrServerClosed) ||
+		errors.Is(err, cmux.ErrListenerClosed) ||
+		errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
 
-	if app.afterShutdown != nil {
-		err := app.afterShutdown(app.ctx)
-		if err != nil {
-			return
+// register the service instance to registry if specified
+func (app *Application) register() error {
+	if app.registry == nil {
+		return nil
+	}
+
+	instance, err := app.buildInstance()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(app.ctx, time.Second*10)
+	defer cancel()
+	if err = app.registry.Register(ctx, instance); err != nil {
+		return err
+	}
+
+	app.instance = instance
+	app.logger.Info("[Registry] registered", slog.String("name", instance.Name),
+		slog.String("id", instance.ID), slog.Any("endpoints", instance.Endpoints))
+	return nil
+}
+
+// unregister the service instance from registry if registered
+func (app *Application) unregister() {
+	if app.registry == nil || app.instance == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	if err := app.registry.UnRegister(ctx, app.instance); err != nil {
+		app.logger.Error("[Registry] unregister failed", slog.Any("err", err))
+	}
+	app.instance = nil
+}
+
+func (app *Application) buildInstance() (*registry.ServiceInstance, error) {
+	endpoints := app.endpoints
+	if len(endpoints) == 0 {
+		schemes := []string{"http", "grpc"}
+		for i, lis := range []net.Listener{app.http.Listener, app.grpc.Listener} {
+			endpoint, err := endpointOf(schemes[i], lis.Addr())
+			if err != nil {
+				return nil, err
+			}
+			endpoints = append(endpoints, endpoint)
 		}
 	}
+
+	return &registry.ServiceInstance{
+		ID:        app.id,
+		Name:      app.name,
+		Version:   app.version,
+		Metadata:  app.metadata,
+		Endpoints: endpoints,
+	}, nil
+}
+
+// endpointOf returns scheme://host:port, the unspecified host
+// will be replaced with the intranet ip
+func endpointOf(scheme string, addr net.Addr) (string, error) {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return "", err
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		intranet, ok := utility.IntranetIp()
+		if !ok {
+			return "", fmt.Errorf("can not resolve intranet ip for %s endpoint", scheme)
+		}
+		host = intranet.String()
+	}
+
+	u := url.URL{Scheme: scheme, Host: net.JoinHostPort(host, port)}
+	return u.String(), nil
 }
@@ -0,0 +1,116 @@
+package pallas
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charliego3/pallas/health"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestShutdown(t *testing.T) {
+	app := NewApp(WithTCPAddr("127.0.0.1:0"), WithSignals())
+	if err := app.Shutdown(); err != nil {
+		t.Fatalf("shutdown before run: %v", err)
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- app.Run(context.Background())
+	}()
+
+	url := "http://" + app.Address().String() + health.ReadyzURI
+	var res *http.Response
+	var err error
+	for i := 0; i < 50; i++ {
+		if res, err = http.Get(url); err == nil {
+			_ = res.Body.Close()
+			// the readiness is resumed after the servers started
+			if res.StatusCode == http.StatusOK {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("readyz status = %d, want 200", res.StatusCode)
+	}
+
+	if err = app.Shutdown(); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	select {
+	case err = <-errC:
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run does not return after shutdown")
+	}
+}
+
+func TestGRPCHealth(t *testing.T) {
+	var healthy atomic.Bool
+	app := NewApp(WithTCPAddr("127.0.0.1:0"), WithSignals(),
+		WithHealthInterval(20*time.Millisecond),
+		WithHealthCheck("db", func(context.Context) error {
+			if healthy.Load() {
+				return nil
+			}
+			return errors.New("connection refused")
+		}),
+	)
+	go func() { _ = app.Run(context.Background()) }()
+	defer app.Shutdown()
+
+	conn, err := grpc.Dial(app.Address().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var stream grpc_health_v1.Health_WatchClient
+	for stream == nil {
+		if stream, err = grpc_health_v1.NewHealthClient(conn).Watch(ctx, new(grpc_health_v1.HealthCheckRequest)); err != nil {
+			stream = nil
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	// the failed check at startup is never reported as SERVING
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status == grpc_health_v1.HealthCheckResponse_SERVING {
+			t.Fatal("grpc health is SERVING while the readiness check failed")
+		}
+		if res.Status == grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+			break
+		}
+	}
+
+	healthy.Store(true)
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status == grpc_health_v1.HealthCheckResponse_SERVING {
+			return
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charliego3/pallas/errorx"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	errorxPackage = protogen.GoImportPath("github.com/charliego3/pallas/errorx")
+	codesPackage  = protogen.GoImportPath("google.golang.org/grpc/codes")
+)
+
+// reason is an enum value of the error catalog
+type reason struct {
+	value *protogen.EnumValue
+	code  codes.Code
+}
+
+// generate generates a _errors.pb.go file containing the helpers of
+// the enums which are annotated with (pallas.errorx.default_code)
+func generate(gen *protogen.Plugin, f *protogen.File) {
+	var enums []*protogen.Enum
+	for _, e := range f.Enums {
+		if proto.HasExtension(e.Desc.Options(), errorx.E_DefaultCode) {
+			enums = append(enums, e)
+		}
+	}
+	if len(enums) == 0 {
+		return
+	}
+
+	g := addHeader(gen, f, f.GeneratedFilenamePrefix+"_errors.pb.go")
+	g.P("// This is a compile-time assertion to ensure that this generated file")
+	g.P("// is compatible with the pallas package it is being compiled against.")
+	g.P("var _ = new(", errorxPackage.Ident("Error"), ")")
+	g.P("var _ = ", codesPackage.Ident("OK"))
+	g.P()
+	for _, e := range enums {
+		reasons, err := getReasons(e)
+		if err != nil {
+			gen.Error(err)
+			return
+		}
+		for _, r := range reasons {
+			generateReason(g, e, r)
+		}
+	}
+}
+
+// getReasons returns the values with the code option,
+// the default code of enum is used if absent
+func getReasons(e *protogen.Enum) ([]reason, error) {
+	defaultCode := proto.GetExtension(e.Desc.Options(), errorx.E_DefaultCode).(code.Code)
+	reasons := make([]reason, 0, len(e.Values))
+	for _, v := range e.Values {
+		c := defaultCode
+		if opts := v.Desc.Options().(*descriptorpb.EnumValueOptions); proto.HasExtension(opts, errorx.E_Code) {
+			c = proto.GetExtension(opts, errorx.E_Code).(code.Code)
+		}
+		if c == code.Code_OK {
+			return nil, fmt.Errorf("%s: the code of error reason must not be OK", v.Desc.FullName())
+		}
+		reasons = append(reasons, reason{value: v, code: codes.Code(c)})
+	}
+	return reasons, nil
+}
+
+func generateReason(g *protogen.GeneratedFile, e *protogen.Enum, r reason) {
+	name := camelCase(string(r.value.Desc.Name()))
+	deprecated := r.value.Desc.Options().(*descriptorpb.EnumValueOptions).GetDeprecated()
+	codeIdent := g.QualifiedGoIdent(codesPackage.Ident(r.code.String()))
+	reasonIdent := g.QualifiedGoIdent(r.value.GoIdent) + ".String()"
+
+	g.P("// Is", name, " reports whether err is ", r.value.Desc.Name(), " of ", e.Desc.Name())
+	if deprecated {
+		g.P("//")
+		g.P("// Deprecated: Do not use.")
+	}
+	g.P("func Is", name, "(err error) bool {")
+	g.P("\tif err == nil {")
+	g.P("\t\treturn false")
+	g.P("\t}")
+	g.P("\te := ", errorxPackage.Ident("FromError"), "(err)")
+	g.P("\treturn e.Code == ", codeIdent, " && e.Reason == ", reasonIdent)
+	g.P("}")
+	g.P()
+
+	g.P("// Error", name, " returns ", r.value.Desc.Name(), " error with ", r.code.String(), " code")
+	if leading := r.value.Comments.Leading; leading != "" {
+		g.P("//")
+		g.P(strings.TrimSuffix(leading.String(), "\n"))
+	}
+	if deprecated {
+		g.P("//")
+		g.P("// Deprecated: Do not use.")
+	}
+	g.P("func Error", name, "(format string, args ...any) *", errorxPackage.Ident("Error"), " {")
+	g.P("\treturn ", errorxPackage.Ident("Newf"), "(", codeIdent, ", ", reasonIdent, ", format, args...)")
+	g.P("}")
+	g.P()
+}
+
+// camelCase converts USER_NOT_FOUND to UserNotFound
+func camelCase(s string) string {
+	b := make([]byte, 0, len(s))
+	upper := true
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_':
+			upper = true
+			continue
+		case upper && 'a' <= c && c <= 'z':
+			c -= 'a' - 'A'
+		case !upper && 'A' <= c && c <= 'Z':
+			c += 'a' - 'A'
+		}
+		upper = '0' <= c && c <= '9'
+		b = append(b, c)
+	}
+	return string(b)
+}
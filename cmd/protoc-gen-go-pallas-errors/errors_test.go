@@ -0,0 +1,128 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charliego3/pallas/errorx"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestCamelCase(t *testing.T) {
+	tests := map[string]string{
+		"USER_NOT_FOUND": "UserNotFound",
+		"user_not_found": "UserNotFound",
+		"ORDER_2FA":      "Order2Fa",
+		"V2_LIMIT":       "V2Limit",
+		"_LEADING":       "Leading",
+		"TRAILING_":      "Trailing",
+		"A__B":           "AB",
+		"UNKNOWN":        "Unknown",
+	}
+	for in, want := range tests {
+		if got := camelCase(in); got != want {
+			t.Errorf("camelCase(%s) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+// request builds the CodeGeneratorRequest of a small errors.proto
+func request(values ...*descriptorpb.EnumValueDescriptorProto) *pluginpb.CodeGeneratorRequest {
+	enumOpts := new(descriptorpb.EnumOptions)
+	proto.SetExtension(enumOpts, errorx.E_DefaultCode, code.Code_INTERNAL)
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/errors.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{errorx.File_errorx_errors_proto.Path()},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test;test")},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    proto.String("ErrorReason"),
+			Value:   values,
+			Options: enumOpts,
+		}},
+		SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{{
+			Path:            []int32{5, 0, 2, 1},
+			Span:            []int32{1, 0, 1},
+			LeadingComments: proto.String(" the user is not found\n"),
+		}}},
+	}
+
+	req := &pluginpb.CodeGeneratorRequest{FileToGenerate: []string{file.GetName()}}
+	deps := errorx.File_errorx_errors_proto.Imports()
+	for i := 0; i < deps.Len(); i++ {
+		req.ProtoFile = append(req.ProtoFile, protodesc.ToFileDescriptorProto(deps.Get(i).FileDescriptor))
+	}
+	req.ProtoFile = append(req.ProtoFile, protodesc.ToFileDescriptorProto(errorx.File_errorx_errors_proto), file)
+	return req
+}
+
+func enumValue(name string, number int32, c *code.Code, deprecated bool) *descriptorpb.EnumValueDescriptorProto {
+	opts := &descriptorpb.EnumValueOptions{Deprecated: proto.Bool(deprecated)}
+	if c != nil {
+		proto.SetExtension(opts, errorx.E_Code, *c)
+	}
+	return &descriptorpb.EnumValueDescriptorProto{Name: proto.String(name), Number: proto.Int32(number), Options: opts}
+}
+
+func run(t *testing.T, req *pluginpb.CodeGeneratorRequest) *pluginpb.CodeGeneratorResponse {
+	t.Helper()
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range gen.Files {
+		if f.Generate {
+			generate(gen, f)
+		}
+	}
+	return gen.Response()
+}
+
+func TestGenerate(t *testing.T) {
+	notFound := code.Code_NOT_FOUND
+	resp := run(t, request(
+		enumValue("UNKNOWN_ERROR", 0, nil, false),
+		enumValue("USER_NOT_FOUND", 1, &notFound, false),
+		enumValue("LEGACY_ERROR", 2, nil, true),
+	))
+	if resp.GetError() != "" {
+		t.Fatal(resp.GetError())
+	}
+	if len(resp.File) != 1 || resp.File[0].GetName() != "example.com/test/errors_errors.pb.go" {
+		t.Fatalf("files = %v", resp.File)
+	}
+
+	content := resp.File[0].GetContent()
+	for _, want := range []string{
+		"package test",
+		"// source file: test/errors.proto",
+		"func IsUnknownError(err error) bool {",
+		"return e.Code == codes.Internal && e.Reason == ErrorReason_UNKNOWN_ERROR.String()",
+		"func ErrorUnknownError(format string, args ...any) *errorx.Error {",
+		"return e.Code == codes.NotFound && e.Reason == ErrorReason_USER_NOT_FOUND.String()",
+		"// ErrorUserNotFound returns USER_NOT_FOUND error with NotFound code\n//\n// the user is not found\n",
+		"// Deprecated: Do not use.\nfunc IsLegacyError(err error) bool {",
+		"return errorx.Newf(codes.Internal, ErrorReason_LEGACY_ERROR.String(), format, args...)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated file does not contain %q", want)
+		}
+	}
+	if t.Failed() {
+		t.Log(content)
+	}
+}
+
+func TestGenerateOK(t *testing.T) {
+	ok := code.Code_OK
+	resp := run(t, request(enumValue("SUCCESS", 0, &ok, false)))
+	if !strings.Contains(resp.GetError(), "must not be OK") {
+		t.Fatalf("error = %q, want the OK code rejected", resp.GetError())
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charliego3/pallas/middleware/authz/authzpb"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+)
+
+// rulePackage has no dependencies, the generated file must not
+// import authz which loads the config on init
+const rulePackage = protogen.GoImportPath("github.com/charliego3/pallas/middleware/authz/rule")
+
+// generateAuthz generates a _authz.pb.go file containing the rules
+// of the (pallas.authz.rule) method options by service
+func generateAuthz(gen *protogen.Plugin, f *protogen.File) {
+	if !hasAuthzRule(f) {
+		return
+	}
+
+	g := addHeader(gen, f, f.GeneratedFilenamePrefix+"_authz.pb.go")
+	for _, s := range f.Services {
+		if !hasServiceAuthzRule(s) {
+			continue
+		}
+
+		g.P("// ", s.GoName, "_AuthzRules is the authorization rules of ", s.GoName, " by operation,")
+		g.P("// see authz.WithRules")
+		g.P("var ", s.GoName, "_AuthzRules = map[string]", rulePackage.Ident("Rule"), "{")
+		for _, m := range s.Methods {
+			rule := authzRule(m)
+			if rule == nil {
+				continue
+			}
+
+			var fields []string
+			if rule.GetPublic() {
+				fields = append(fields, "Public: true")
+			}
+			for _, list := range []struct {
+				name   string
+				values []string
+			}{
+				{"Roles", rule.GetRoles()},
+				{"Scopes", rule.GetScopes()},
+				{"Subjects", rule.GetSubjects()},
+			} {
+				if len(list.values) > 0 {
+					fields = append(fields, list.name+": "+stringSlice(list.values))
+				}
+			}
+			if attrs := rule.GetAttributes(); len(attrs) > 0 {
+				fields = append(fields, "Attributes: "+attributesMap(attrs))
+			}
+			operation := "/" + string(s.Desc.FullName()) + "/" + string(m.Desc.Name())
+			g.P("\t", strconv.Quote(operation), ": {", strings.Join(fields, ", "), "},")
+		}
+		g.P("}")
+		g.P()
+	}
+}
+
+// authzRule returns the (pallas.authz.rule) option of method, nil if absent
+func authzRule(m *protogen.Method) *authzpb.Requirement {
+	opts := m.Desc.Options()
+	if !proto.HasExtension(opts, authzpb.E_Rule) {
+		return nil
+	}
+	return proto.GetExtension(opts, authzpb.E_Rule).(*authzpb.Requirement)
+}
+
+func hasServiceAuthzRule(s *protogen.Service) bool {
+	for _, m := range s.Methods {
+		if authzRule(m) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAuthzRule(f *protogen.File) bool {
+	for _, s := range f.Services {
+		if hasServiceAuthzRule(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func stringSlice(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// attributesMap returns the map literal of attributes sorted by key
+func attributesMap(attrs map[string]*authzpb.Values) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	entries := make([]string, len(keys))
+	for i, k := range keys {
+		entries[i] = strconv.Quote(k) + ": " + strings.TrimPrefix(stringSlice(attrs[k].GetValues()), "[]string")
+	}
+	return "map[string][]string{" + strings.Join(entries, ", ") + "}"
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charliego3/pallas/middleware/authz/authzpb"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func authzMethod(name string, rule *authzpb.Requirement) *descriptorpb.MethodDescriptorProto {
+	m := &descriptorpb.MethodDescriptorProto{
+		Name:       proto.String(name),
+		InputType:  proto.String(".test.Request"),
+		OutputType: proto.String(".test.Request"),
+	}
+	if rule != nil {
+		m.Options = new(descriptorpb.MethodOptions)
+		proto.SetExtension(m.Options, authzpb.E_Rule, rule)
+	}
+	return m
+}
+
+func TestGenerateAuthz(t *testing.T) {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("test/user.proto"),
+		Package:     proto.String("test"),
+		Syntax:      proto.String("proto3"),
+		Dependency:  []string{authzpb.File_middleware_authz_authzpb_authz_proto.Path()},
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test;test")},
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Request")}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("User"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				authzMethod("Login", &authzpb.Requirement{Public: true}),
+				authzMethod("Profile", nil),
+				authzMethod("Delete", &authzpb.Requirement{
+					Roles:  []string{"admin"},
+					Scopes: []string{"user:write"},
+					Attributes: map[string]*authzpb.Values{
+						"tenant": {Values: []string{"acme", "globex"}},
+						"region": {Values: []string{"eu"}},
+					},
+				}),
+			},
+		}, {
+			Name:   proto.String("Public"),
+			Method: []*descriptorpb.MethodDescriptorProto{authzMethod("Ping", nil)},
+		}},
+	}
+
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{file.GetName()},
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+			protodesc.ToFileDescriptorProto(authzpb.File_middleware_authz_authzpb_authz_proto),
+			file,
+		},
+	}
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range gen.Files {
+		if f.Generate {
+			generateAuthz(gen, f)
+		}
+	}
+
+	resp := gen.Response()
+	if resp.GetError() != "" || len(resp.File) != 1 {
+		t.Fatalf("response = %v", resp)
+	}
+	content := resp.File[0].GetContent()
+	for _, want := range []string{
+		"var User_AuthzRules = map[string]rule.Rule{",
+		`"/test.User/Login":  {Public: true},`,
+		`"/test.User/Delete": {Roles: []string{"admin"}, Scopes: []string{"user:write"}, ` +
+			`Attributes: map[string][]string{"region": {"eu"}, "tenant": {"acme", "globex"}}},`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated file does not contain %q", want)
+		}
+	}
+	for _, unwanted := range []string{"Profile", "Public_AuthzRules", "middleware/authz\""} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("generated file contains %q", unwanted)
+		}
+	}
+	if t.Failed() {
+		t.Log(content)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"google.golang.org/genproto/googleapis/api/annotations"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
@@ -17,11 +18,25 @@ const (
 )
 
 type method struct {
-	name    string
-	method  string
-	path    string
-	handler string
-	in, out string
+	name string
+
+	// operation is the full grpc method, eg: /pb.User/Login
+	operation string
+	method    string
+	path      string
+	handler   string
+	in, out   string
+
+	// body is the HttpRule body, bodyField is the body field
+	// when it's neither empty nor "*"
+	body      string
+	bodyField *protogen.Field
+
+	// responseBody is the HttpRule response_body field
+	responseBody *protogen.Field
+
+	// additional is true if the method is from additional bindings
+	additional bool
 }
 
 // generate generates a _http.http.go file containing HTTP service definitions.
@@ -46,8 +61,9 @@ func generate(gen *protogen.Plugin, f *protogen.File) {
 	dg.P("var _ = new(", typesPackage.Ident("Service"), ")")
 	dg.P()
 	for _, s := range f.Services {
-		methods := getMethods(s)
+		methods := getMethods(gen, s)
 		generateService(gen, hg, s, methods)
+		generateClient(gen, hg, s, methods)
 		generateDesc(gen, dg, s, methods)
 	}
 }
@@ -62,7 +78,7 @@ func checkDeprecate(s *protogen.Service, g *protogen.GeneratedFile) {
 func generateService(gen *protogen.Plugin, g *protogen.GeneratedFile, s *protogen.Service, methods []method) {
 	checkDeprecate(s, g)
 	g.P("type ", s.GoName, "HTTPServer interface {")
-	for _, m := range methods {
+	for _, m := range primaries(methods) {
 		g.P("\t", m.name, "(ctx context.Context, in *", m.in, ") (*", m.out, ", error)")
 	}
 	g.P("}")
@@ -70,7 +86,7 @@ func generateService(gen *protogen.Plugin, g *protogen.GeneratedFile, s *protoge
 
 	g.P("func Register", s.GoName, "HTTPServer(s *httpx.Server, srv ", s.GoName, "HTTPServer) {")
 	for _, m := range methods {
-		g.P("\ts.HandleMethod(\"", m.method, "\", \"", m.path, "\", ", m.handler, "(srv.(types.Service)).(httpx.Handler))")
+		g.P("\ts.HandleOperation(\"", m.operation, "\", \"", m.method, "\", \"", m.path, "\", ", m.handler, "(srv.(types.Service)).(httpx.Handler))")
 	}
 	g.P("}")
 	g.P()
@@ -79,17 +95,103 @@ func generateService(gen *protogen.Plugin, g *protogen.GeneratedFile, s *protoge
 		g.P("func ", m.handler, "(srv types.Service) any {")
 		g.P("\treturn httpx.Handler(func(ctx *httpx.Context) (any, error) {")
 		g.P("\t\treq := new(", m.in, ")")
-		g.P("\t\tif err := ctx.Bind(req); err != nil {")
-		g.P("\t\t\treturn nil, err")
-		g.P("\t\t}")
+		switch m.body {
+		case "*":
+			generateBind(g, "ctx.BindBody(req)")
+		case "":
+			generateBind(g, "ctx.BindQuery(req)")
+		default:
+			generateBind(g, "ctx.BindQuery(req)")
+			if m.bodyField.Message != nil && m.bodyField.Desc.Cardinality() != protoreflect.Repeated {
+				g.P("\t\treq.", m.bodyField.GoName, " = new(", m.bodyField.Message.GoIdent, ")")
+				generateBind(g, "ctx.BindBody(req."+m.bodyField.GoName+")")
+			} else {
+				generateBind(g, "ctx.BindBody(&req."+m.bodyField.GoName+")")
+			}
+		}
+		generateBind(g, "ctx.BindVars(req)")
 		g.P("\t\tctx.Payload = req")
-		g.P("\t\treturn srv.(", s.GoName, "Server).", m.name, "(ctx, req)")
+		if m.responseBody == nil {
+			g.P("\t\treturn srv.(", s.GoName, "Server).", m.name, "(ctx, req)")
+		} else {
+			g.P("\t\treply, err := srv.(", s.GoName, "Server).", m.name, "(ctx, req)")
+			g.P("\t\tif err != nil {")
+			g.P("\t\t\treturn nil, err")
+			g.P("\t\t}")
+			g.P("\t\treturn reply.Get", m.responseBody.GoName, "(), nil")
+		}
 		g.P("\t})")
 		g.P("}")
 		g.P()
 	}
 }
 
+// generateBind generates the binding statement which returns on error
+func generateBind(g *protogen.GeneratedFile, bind string) {
+	g.P("\t\tif err := ", bind, "; err != nil {")
+	g.P("\t\t\treturn nil, err")
+	g.P("\t\t}")
+}
+
+// primaries returns the methods which are not from additional bindings
+func primaries(methods []method) []method {
+	var ms []method
+	for _, m := range methods {
+		if !m.additional {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+func generateClient(gen *protogen.Plugin, g *protogen.GeneratedFile, s *protogen.Service, methods []method) {
+	checkDeprecate(s, g)
+	g.P("type ", s.GoName, "HTTPClient interface {")
+	for _, m := range primaries(methods) {
+		g.P("\t", m.name, "(ctx context.Context, in *", m.in, ", opts ...httpx.CallOption) (*", m.out, ", error)")
+	}
+	g.P("}")
+	g.P()
+
+	impl := s.GoName + "HTTPClientImpl"
+	g.P("type ", impl, " struct {")
+	g.P("\tcc *httpx.Client")
+	g.P("}")
+	g.P()
+	g.P("func New", s.GoName, "HTTPClient(client *httpx.Client) ", s.GoName, "HTTPClient {")
+	g.P("\treturn &", impl, "{client}")
+	g.P("}")
+	g.P()
+
+	for _, m := range primaries(methods) {
+		body := "nil"
+		switch m.body {
+		case "":
+		case "*":
+			body = "in"
+		default:
+			body = "in." + m.bodyField.GoName
+		}
+		g.P("func (c *", impl, ") ", m.name, "(ctx context.Context, in *", m.in, ", opts ...httpx.CallOption) (*", m.out, ", error) {")
+		g.P("\tout := new(", m.out, ")")
+		g.P("\tpath := httpx.EncodeURL(\"", m.path, "\", in, \"", m.body, "\")")
+		reply := "out"
+		if f := m.responseBody; f != nil {
+			if f.Message != nil && f.Desc.Cardinality() != protoreflect.Repeated {
+				g.P("\tout.", f.GoName, " = new(", f.Message.GoIdent, ")")
+				reply = "out." + f.GoName
+			} else {
+				reply = "&out." + f.GoName
+			}
+		}
+		g.P("\topts = append([]httpx.CallOption{httpx.Operation(\"", m.operation, "\"), httpx.PathTemplate(\"", m.path, "\")}, opts...)")
+		g.P("\terr := c.cc.Invoke(ctx, \"", m.method, "\", path, ", body, ", ", reply, ", opts...)")
+		g.P("\treturn out, err")
+		g.P("}")
+		g.P()
+	}
+}
+
 func generateDesc(gen *protogen.Plugin, g *protogen.GeneratedFile, s *protogen.Service, methods []method) {
 	checkDeprecate(s, g)
 	name := "Unimplemented" + s.GoName + "DescServer"
@@ -108,13 +210,14 @@ func generateDesc(gen *protogen.Plugin, g *protogen.GeneratedFile, s *protogen.S
 		g.P("\t\t\t\t{")
 		g.P("\t\t\t\t\tMethod: \"", m.method, "\",")
 		g.P("\t\t\t\t\tTemplate: \"", m.path, "\",")
+		g.P("\t\t\t\t\tOperation: \"", m.operation, "\",")
 		g.P("\t\t\t\t\tHandler: ", m.handler, ",")
 		g.P("\t\t\t\t},")
 	}
 	g.P("\t\t\t},\n\t\t},\n\t}\n}\n")
 }
 
-func getMethods(s *protogen.Service) (requests []method) {
+func getMethods(gen *protogen.Plugin, s *protogen.Service) (requests []method) {
 	for _, m := range s.Methods {
 		desc := m.Desc
 		if desc.IsStreamingClient() || desc.IsStreamingServer() {
@@ -125,7 +228,7 @@ func getMethods(s *protogen.Service) (requests []method) {
 			m.Desc.Options(),
 			annotations.E_Http,
 		).(*annotations.HttpRule); ok {
-			for _, binding := range append(rule.AdditionalBindings, rule) {
+			for i, binding := range append(rule.AdditionalBindings, rule) {
 				var method method
 				switch pattern := binding.Pattern.(type) {
 				case *annotations.HttpRule_Get:
@@ -148,6 +251,25 @@ func getMethods(s *protogen.Service) (requests []method) {
 					method.method = pattern.Custom.Kind
 				}
 				method.name = m.GoName
+				method.operation = fmt.Sprintf("/%s/%s", s.Desc.FullName(), m.Desc.Name())
+				method.body = binding.Body
+				method.additional = i < len(rule.AdditionalBindings)
+				if method.body != "" && method.body != "*" {
+					method.bodyField = findField(m.Input, method.body)
+					if method.bodyField == nil {
+						gen.Error(fmt.Errorf("%s: body field %q not found in %s",
+							m.Desc.FullName(), method.body, m.Input.Desc.FullName()))
+						continue
+					}
+				}
+				if binding.ResponseBody != "" {
+					method.responseBody = findField(m.Output, binding.ResponseBody)
+					if method.responseBody == nil {
+						gen.Error(fmt.Errorf("%s: response_body field %q not found in %s",
+							m.Desc.FullName(), binding.ResponseBody, m.Output.Desc.FullName()))
+						continue
+					}
+				}
 				method.handler = fmt.Sprintf("_%s_%s_%s_HTTP_Handler", s.GoName, m.GoName, method.method)
 				method.in = string(m.Desc.Input().Name())
 				method.out = string(m.Desc.Output().Name())
@@ -158,6 +280,16 @@ func getMethods(s *protogen.Service) (requests []method) {
 	return
 }
 
+// findField returns the top-level field of message by proto name
+func findField(message *protogen.Message, name string) *protogen.Field {
+	for _, field := range message.Fields {
+		if string(field.Desc.Name()) == name {
+			return field
+		}
+	}
+	return nil
+}
+
 func hasHTTPMethod(f *protogen.File) bool {
 	for _, serv := range f.Services {
 		for _, method := range serv.Methods {
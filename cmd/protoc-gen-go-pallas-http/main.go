@@ -31,6 +31,7 @@ func main() {
 			}
 
 			generate(gen, f)
+			generateAuthz(gen, f)
 		}
 		return nil
 	})
@@ -0,0 +1,141 @@
+package pallas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/charliego3/pallas/types"
+	"github.com/charliego3/pallas/utility"
+)
+
+// Supervisor runs the Component and restarts it with backoff on failure
+// if restart enabled, otherwise the failure stops the Application
+type Supervisor struct {
+	name      string
+	component types.Component
+	logger    *slog.Logger
+
+	// restart the component on failure if true
+	restart bool
+
+	// backoff is the initial waiting duration before restart,
+	// it's doubled after each failure and limited by maxBackoff
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	// maxRestarts is the maximum restart times, 0 means unlimited
+	maxRestarts int
+
+	// stopping is closed once Stop called, the component
+	// is finished rather than failed if Start returns after it
+	stopping chan struct{}
+	once     sync.Once
+}
+
+// WithRestart restarts the component on failure, the waiting duration
+// starts at backoff and doubles after each failure up to maxBackoff
+func WithRestart(backoff, maxBackoff time.Duration) utility.Option[Supervisor] {
+	return utility.OptionFunc[Supervisor](func(s *Supervisor) {
+		s.restart = true
+		s.backoff = backoff
+		s.maxBackoff = max(backoff, maxBackoff)
+	})
+}
+
+// WithMaxRestarts limit the restart times, the Application fails
+// when the component failed after n restarts
+func WithMaxRestarts(n int) utility.Option[Supervisor] {
+	return utility.OptionFunc[Supervisor](func(s *Supervisor) {
+		s.maxRestarts = n
+	})
+}
+
+func newSupervisor(name string, c types.Component, opts ...utility.Option[Supervisor]) *Supervisor {
+	s := &Supervisor{
+		name:       name,
+		component:  c,
+		logger:     slog.Default(),
+		backoff:    time.Second,
+		maxBackoff: time.Minute,
+		stopping:   make(chan struct{}),
+	}
+	utility.Apply(s, opts...)
+	return s
+}
+
+// Run starts the component until ctx is done, Stop called or it's finished
+func (s *Supervisor) Run(ctx context.Context) error {
+	backoff := s.backoff
+	for restarts := 0; ; restarts++ {
+		begin := time.Now()
+		err := s.start(ctx)
+		if ctx.Err() != nil || s.isStopping() {
+			return nil
+		}
+		if err == nil {
+			s.logger.Info("[Component] finished", slog.String("name", s.name))
+			return nil
+		}
+		if !s.restart || (s.maxRestarts > 0 && restarts >= s.maxRestarts) {
+			return fmt.Errorf("[Component] %s failed: %w", s.name, err)
+		}
+
+		// the component ran long enough, it's not a continuous failure
+		if time.Since(begin) > s.maxBackoff {
+			backoff = s.backoff
+		}
+		s.logger.Warn("[Component] failed, restarting", slog.String("name", s.name),
+			slog.Duration("backoff", backoff), slog.Any("err", err))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.stopping:
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, s.maxBackoff)
+	}
+}
+
+// start calls Component.Start and converts panic to error
+func (s *Supervisor) start(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return s.component.Start(ctx)
+}
+
+// isStopping reports whether Stop is called
+func (s *Supervisor) isStopping() bool {
+	select {
+	case <-s.stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop marks the supervisor stopping so that the component
+// is no longer restarted, then stops the component
+func (s *Supervisor) Stop(ctx context.Context) error {
+	s.once.Do(func() { close(s.stopping) })
+	if err := s.component.Stop(ctx); err != nil {
+		return fmt.Errorf("[Component] %s stop: %w", s.name, err)
+	}
+	return nil
+}
+
+// stopComponents stops the components in reverse order
+func (app *Application) stopComponents(ctx context.Context) error {
+	errs := make([]error, 0, len(app.components))
+	for i := len(app.components) - 1; i >= 0; i-- {
+		errs = append(errs, app.components[i].Stop(ctx))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,103 @@
+package pallas
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type flakyComponent struct {
+	failures int32
+	starts   atomic.Int32
+}
+
+func (c *flakyComponent) Start(ctx context.Context) error {
+	if c.starts.Add(1) <= c.failures {
+		return errors.New("connection refused")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (c *flakyComponent) Stop(context.Context) error {
+	return nil
+}
+
+func TestSupervisorRestart(t *testing.T) {
+	c := &flakyComponent{failures: 3}
+	s := newSupervisor("consumer", c, WithRestart(time.Millisecond, 5*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errC := make(chan error, 1)
+	go func() {
+		errC <- s.Run(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	if err := <-errC; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if starts := c.starts.Load(); starts != 4 {
+		t.Fatalf("starts = %d, want 4", starts)
+	}
+}
+
+func TestSupervisorFailure(t *testing.T) {
+	c := &flakyComponent{failures: 3}
+	s := newSupervisor("consumer", c, WithRestart(time.Millisecond, time.Millisecond), WithMaxRestarts(1))
+	if err := s.Run(context.Background()); err == nil {
+		t.Fatal("run should fail after max restarts")
+	}
+	if starts := c.starts.Load(); starts != 2 {
+		t.Fatalf("starts = %d, want 2", starts)
+	}
+
+	s = newSupervisor("consumer", &flakyComponent{failures: 1})
+	if err := s.Run(context.Background()); err == nil {
+		t.Fatal("run should fail without restart")
+	}
+}
+
+// stoppingComponent returns an error from Start once Stop called
+type stoppingComponent struct {
+	stopC chan struct{}
+}
+
+func (c *stoppingComponent) Start(context.Context) error {
+	<-c.stopC
+	return errors.New("consumer closed")
+}
+
+func (c *stoppingComponent) Stop(context.Context) error {
+	close(c.stopC)
+	return nil
+}
+
+func TestSupervisorStop(t *testing.T) {
+	for _, restart := range []bool{false, true} {
+		c := &stoppingComponent{stopC: make(chan struct{})}
+		s := newSupervisor("consumer", c)
+		if restart {
+			s = newSupervisor("consumer", c, WithRestart(time.Millisecond, time.Millisecond))
+		}
+
+		errC := make(chan error, 1)
+		go func() {
+			errC <- s.Run(context.Background())
+		}()
+		if err := s.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case err := <-errC:
+			if err != nil {
+				t.Fatalf("restart %v: run = %v, want finished", restart, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("restart %v: the stopped component is restarted", restart)
+		}
+	}
+}
@@ -18,6 +18,7 @@ type StandardConfig struct {
 	Database *Database `json:"database,omitempty" yaml:"database,omitempty" toml:"database,omitempty"`
 	Redis    *Redis    `json:"redis,omitempty" yaml:"redis,omitempty" toml:"redis,omitempty"`
 	Logger   *Logger   `json:"logger,omitempty" yaml:"logger,omitempty" toml:"logger,omitempty"`
+	Authz    *Authz    `json:"authz,omitempty" yaml:"authz,omitempty" toml:"authz,omitempty"`
 }
 
 var standard = StandardConfig{
@@ -56,6 +57,7 @@ func init() {
 	register[Redis](standard.Redis, &standardRedisFetcher{})
 	register[Database](standard.Database, &standardDatabaseFetcher{})
 	register[Logger](standard.Logger, &standardLoggerConfig{})
+	register[Authz](standard.Authz, &standardAuthzFetcher{})
 }
 
 // register register fetcher to fetchers if obj is not nil
@@ -66,3 +68,12 @@ func register[T any](obj *T, fetcher Fetcher[T]) {
 
 	RegisterFetcher(fetcher)
 }
+
+// deref returns the value of standard section, ErrNotFound if it's absent
+func deref[T any](obj *T) (T, error) {
+	if obj == nil {
+		var t T
+		return t, ErrNotFound
+	}
+	return *obj, nil
+}
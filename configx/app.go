@@ -1,12 +1,17 @@
 package configx
 
 type App struct {
+	Name    string `json:"name,omitempty" yaml:"name,omitempty"`
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
 	Network string `json:"network,omitempty" yaml:"network,omitempty"`
 	Address string `json:"address,omitempty" yaml:"address,omitempty"`
+
+	// Admin is the internal-only address of the admin server, eg: 127.0.0.1:9090
+	Admin string `json:"admin,omitempty" yaml:"admin,omitempty"`
 }
 
 type standardAppFetcher struct{}
 
 func (f *standardAppFetcher) Fetch() (App, error) {
-	return *standard.App, nil
+	return deref(standard.App)
 }
@@ -0,0 +1,38 @@
+package configx
+
+import (
+	"time"
+
+	"github.com/charliego3/pallas/middleware/authz/rule"
+)
+
+// Authz is the authorization config, the rules of File replace
+// the Rules and File is reloaded once it's modified
+type Authz struct {
+	AuthzPolicy `yaml:",inline"`
+
+	// File is the yaml policy file of AuthzPolicy
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+
+	// Interval is the interval of checking the File, default is 10s
+	Interval time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+}
+
+// AuthzPolicy is the rules by operation, the operation ends with
+// * matches the prefix, eg: /grpc.health.v1.Health/*, the longest
+// prefix is used if there's no exactly matched rule
+type AuthzPolicy struct {
+	// Default is the decision of the operations without rule, allow or deny, default is deny
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+
+	Rules map[string]AuthzRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// AuthzRule is the requirements of an operation, see rule.Rule
+type AuthzRule = rule.Rule
+
+type standardAuthzFetcher struct{}
+
+func (f *standardAuthzFetcher) Fetch() (Authz, error) {
+	return deref(standard.Authz)
+}
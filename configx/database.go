@@ -15,5 +15,5 @@ type Database struct {
 type standardDatabaseFetcher struct{}
 
 func (f *standardDatabaseFetcher) Fetch() (Database, error) {
-	return *standard.Database, nil
+	return deref(standard.Database)
 }
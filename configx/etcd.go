@@ -26,5 +26,5 @@ type Etcd struct {
 type standardEtcdFetcher struct{}
 
 func (f *standardEtcdFetcher) Fetch() (Etcd, error) {
-	return *standard.Etcd, nil
+	return deref(standard.Etcd)
 }
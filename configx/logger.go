@@ -17,5 +17,5 @@ type Logger struct {
 type standardLoggerConfig struct{}
 
 func (f *standardLoggerConfig) Fetch() (Logger, error) {
-	return *standard.Logger, nil
+	return deref(standard.Logger)
 }
@@ -0,0 +1,56 @@
+package configx
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// MaskedValue replaces the value of secret keys
+const MaskedValue = "******"
+
+// secretKeys are the substrings of secret keys in lower case
+var secretKeys = []string{"password", "secret", "token", "dsn", "key", "credential"}
+
+// Masked returns the effective standard config with secrets masked,
+// the key is secret if it contains any of password, secret, token,
+// dsn, key and credential in case-insensitive
+func Masked() (map[string]any, error) {
+	data, err := json.Marshal(standard)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]any
+	if err = json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	mask(m)
+	return m, nil
+}
+
+func mask(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, value := range v {
+			if isSecret(k) && value != nil && value != "" {
+				v[k] = MaskedValue
+				continue
+			}
+			mask(value)
+		}
+	case []any:
+		for _, value := range v {
+			mask(value)
+		}
+	}
+}
+
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
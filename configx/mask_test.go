@@ -0,0 +1,24 @@
+package configx
+
+import "testing"
+
+func TestMask(t *testing.T) {
+	m := map[string]any{
+		"database": map[string]any{"dsn": "root:secret@tcp(127.0.0.1)/db"},
+		"redis": map[string]any{
+			"Address":  []any{"127.0.0.1:6379"},
+			"Password": "secret",
+			"Username": "",
+		},
+		"etcd": map[string]any{"pemKey": "key.pem", "username": "root"},
+	}
+	mask(m)
+
+	redis, etcd := m["redis"].(map[string]any), m["etcd"].(map[string]any)
+	if m["database"].(map[string]any)["dsn"] != MaskedValue || redis["Password"] != MaskedValue || etcd["pemKey"] != MaskedValue {
+		t.Fatalf("secrets are not masked: %v", m)
+	}
+	if etcd["username"] != "root" || redis["Username"] != "" {
+		t.Fatalf("non-secret values are masked: %v", m)
+	}
+}
@@ -49,5 +49,5 @@ type Redis struct {
 type standardRedisFetcher struct{}
 
 func (f *standardRedisFetcher) Fetch() (Redis, error) {
-	return *standard.Redis, nil
+	return deref(standard.Redis)
 }
@@ -42,6 +42,12 @@ func RegisterCodec(codec Codec) {
 	registeredCodec[strings.ToLower(typename)] = codec
 }
 
+// LookupCodec returns the registered Codec, ok is false if not registered
+func LookupCodec(typename string) (Codec, bool) {
+	codec, ok := registeredCodec[strings.ToLower(typename)]
+	return codec, ok
+}
+
 func CodecWithType(typename string) Codec {
 	if codec, ok := registeredCodec[strings.ToLower(typename)]; ok {
 		return codec
@@ -5,21 +5,43 @@ import (
 	"io"
 
 	"github.com/charliego3/pallas/encoding"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 // Type json Codec name
 const Type = "json"
 
-// codec is a Codec implemention with json
+var (
+	// MarshalOptions is the protojson options used to marshal proto.Message,
+	// the unpopulated fields are emitted to keep the response shape stable
+	MarshalOptions = protojson.MarshalOptions{
+		EmitUnpopulated: true,
+	}
+
+	// UnmarshalOptions is the protojson options used to unmarshal proto.Message
+	UnmarshalOptions = protojson.UnmarshalOptions{
+		DiscardUnknown: true,
+	}
+)
+
+// codec is a Codec implemention with json,
+// the proto.Message is encoded with protojson
 type codec struct{}
 
 // Marshal data v to bytes, if err not nil
 func (codec) Marshal(v any) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok {
+		return MarshalOptions.Marshal(m)
+	}
 	return json.Marshal(v)
 }
 
 // Unmarshal bytes to any pointer
 func (codec) Unmarshal(data []byte, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		return UnmarshalOptions.Unmarshal(data, m)
+	}
 	return json.Unmarshal(data, v)
 }
 
@@ -29,11 +51,51 @@ func (codec) Type() string {
 }
 
 func (codec) Encoder(w io.Writer) encoding.Encoder {
-	return json.NewEncoder(w)
+	return &encoder{w: w, Encoder: json.NewEncoder(w)}
 }
 
 func (codec) Decoder(r io.Reader) encoding.Decoder {
-	return json.NewDecoder(r)
+	return &decoder{Decoder: json.NewDecoder(r)}
+}
+
+// encoder write proto.Message with protojson,
+// otherwise using encoding/json Encoder
+type encoder struct {
+	*json.Encoder
+	w io.Writer
+}
+
+func (e *encoder) Encode(v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return e.Encoder.Encode(v)
+	}
+
+	data, err := MarshalOptions.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(append(data, '\n'))
+	return err
+}
+
+// decoder read proto.Message with protojson,
+// otherwise using encoding/json Decoder
+type decoder struct {
+	*json.Decoder
+}
+
+func (d *decoder) Decode(v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return d.Decoder.Decode(v)
+	}
+
+	var raw json.RawMessage
+	if err := d.Decoder.Decode(&raw); err != nil {
+		return err
+	}
+	return UnmarshalOptions.Unmarshal(raw, m)
 }
 
 func init() {
@@ -0,0 +1,103 @@
+package json
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charliego3/pallas/testdata/testpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCodecProto(t *testing.T) {
+	detail, err := anypb.New(&testpb.Nested{Value: "detail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		msg  *testpb.Message
+		want []string
+	}{
+		{"int64 as string", &testpb.Message{Id: 9007199254740993, Count: 1}, []string{`"id":"9007199254740993"`, `"count":"1"`}},
+		{"enum as string", &testpb.Message{Kind: testpb.Kind_KIND_ADMIN}, []string{`"kind":"KIND_ADMIN"`}},
+		{"timestamp", &testpb.Message{CreatedAt: timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))}, []string{`"createdAt":"2024-01-02T03:04:05Z"`}},
+		{"duration", &testpb.Message{Ttl: durationpb.New(90 * time.Second)}, []string{`"ttl":"90s"`}},
+		{"wrappers", &testpb.Message{
+			Nickname: wrapperspb.String("nick"),
+			Score:    wrapperspb.Int64(7),
+			Enabled:  wrapperspb.Bool(false),
+		}, []string{`"nickname":"nick"`, `"score":"7"`, `"enabled":false`}},
+		{"any", &testpb.Message{Detail: detail}, []string{`"detail":{"@type":"type.googleapis.com/pallas.testpb.Nested","value":"detail","kinds":[]}`}},
+		{"oneof", &testpb.Message{Contact: &testpb.Message_Phone{Phone: 10086}}, []string{`"phone":10086`}},
+		{"unpopulated", &testpb.Message{}, []string{`"name":""`, `"tags":[]`, `"labels":{}`, `"createdAt":null`}},
+	}
+
+	c := new(codec)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := c.Marshal(tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			compact := strings.ReplaceAll(string(data), " ", "")
+			for _, want := range tt.want {
+				if !strings.Contains(compact, want) {
+					t.Errorf("%s does not contain %s", compact, want)
+				}
+			}
+
+			got := new(testpb.Message)
+			if err = c.Unmarshal(data, got); err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(got, tt.msg) {
+				t.Fatalf("Unmarshal = %v, want %v", got, tt.msg)
+			}
+
+			// the stream encoder and decoder have the same results
+			var buf bytes.Buffer
+			if err = c.Encoder(&buf).Encode(tt.msg); err != nil {
+				t.Fatal(err)
+			}
+			got = new(testpb.Message)
+			if err = c.Decoder(&buf).Decode(got); err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(got, tt.msg) {
+				t.Fatalf("Decode = %v, want %v", got, tt.msg)
+			}
+		})
+	}
+
+	// the unknown fields are discarded
+	got := new(testpb.Message)
+	if err := c.Unmarshal([]byte(`{"name":"a","unknown":1}`), got); err != nil || got.Name != "a" {
+		t.Fatalf("Unmarshal unknown = %v, %v", got, err)
+	}
+	// the proto name is accepted as well
+	if err := c.Unmarshal([]byte(`{"created_at":"2024-01-02T03:04:05Z"}`), got); err != nil || got.CreatedAt.AsTime().Year() != 2024 {
+		t.Fatalf("Unmarshal proto name = %v, %v", got, err)
+	}
+}
+
+func TestCodecNonProto(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	c := new(codec)
+	data, err := c.Marshal(user{Name: "pallas", Age: 1})
+	if err != nil || string(data) != `{"name":"pallas","age":1}` {
+		t.Fatalf("Marshal = %s, %v", data, err)
+	}
+	var got user
+	if err = c.Decoder(bytes.NewReader(data)).Decode(&got); err != nil || got.Name != "pallas" {
+		t.Fatalf("Decode = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,288 @@
+package errorx
+
+import (
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+)
+
+// ClientClosed is the non-standard http status used when the client canceled the request
+const ClientClosed = 499
+
+var codeNames = map[codes.Code]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// CodeName returns the canonical name of code, eg: NOT_FOUND
+func CodeName(code codes.Code) string {
+	if name, ok := codeNames[code]; ok {
+		return name
+	}
+	return code.String()
+}
+
+// CodeFromName returns the code of canonical name, codes.Unknown if not matched
+func CodeFromName(name string) codes.Code {
+	name = strings.ToUpper(name)
+	for code, n := range codeNames {
+		if n == name {
+			return code
+		}
+	}
+	return codes.Unknown
+}
+
+// HTTPStatusFromCode maps the grpc code to http status code
+// https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return ClientClosed
+	case codes.Unknown:
+		return http.StatusInternalServerError
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.Aborted:
+		return http.StatusConflict
+	case codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Internal:
+		return http.StatusInternalServerError
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DataLoss:
+		return http.StatusInternalServerError
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CodeFromHTTPStatus maps the http status code to grpc code
+func CodeFromHTTPStatus(status int) codes.Code {
+	switch status {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case ClientClosed:
+		return codes.Canceled
+	case http.StatusInternalServerError:
+		return codes.Internal
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+
+	if status >= http.StatusOK && status < http.StatusMultipleChoices {
+		return codes.OK
+	}
+	return codes.Unknown
+}
+
+// Canceled returns Error with codes.Canceled
+func Canceled(reason, format string, args ...any) *Error {
+	return Newf(codes.Canceled, reason, format, args...)
+}
+
+// IsCanceled reports whether err is codes.Canceled
+func IsCanceled(err error) bool {
+	return Code(err) == codes.Canceled
+}
+
+// Unknown returns Error with codes.Unknown
+func Unknown(reason, format string, args ...any) *Error {
+	return Newf(codes.Unknown, reason, format, args...)
+}
+
+// IsUnknown reports whether err is codes.Unknown
+func IsUnknown(err error) bool {
+	return Code(err) == codes.Unknown
+}
+
+// InvalidArgument returns Error with codes.InvalidArgument
+func InvalidArgument(reason, format string, args ...any) *Error {
+	return Newf(codes.InvalidArgument, reason, format, args...)
+}
+
+// IsInvalidArgument reports whether err is codes.InvalidArgument
+func IsInvalidArgument(err error) bool {
+	return Code(err) == codes.InvalidArgument
+}
+
+// DeadlineExceeded returns Error with codes.DeadlineExceeded
+func DeadlineExceeded(reason, format string, args ...any) *Error {
+	return Newf(codes.DeadlineExceeded, reason, format, args...)
+}
+
+// IsDeadlineExceeded reports whether err is codes.DeadlineExceeded
+func IsDeadlineExceeded(err error) bool {
+	return Code(err) == codes.DeadlineExceeded
+}
+
+// NotFound returns Error with codes.NotFound
+func NotFound(reason, format string, args ...any) *Error {
+	return Newf(codes.NotFound, reason, format, args...)
+}
+
+// IsNotFound reports whether err is codes.NotFound
+func IsNotFound(err error) bool {
+	return Code(err) == codes.NotFound
+}
+
+// AlreadyExists returns Error with codes.AlreadyExists
+func AlreadyExists(reason, format string, args ...any) *Error {
+	return Newf(codes.AlreadyExists, reason, format, args...)
+}
+
+// IsAlreadyExists reports whether err is codes.AlreadyExists
+func IsAlreadyExists(err error) bool {
+	return Code(err) == codes.AlreadyExists
+}
+
+// PermissionDenied returns Error with codes.PermissionDenied
+func PermissionDenied(reason, format string, args ...any) *Error {
+	return Newf(codes.PermissionDenied, reason, format, args...)
+}
+
+// IsPermissionDenied reports whether err is codes.PermissionDenied
+func IsPermissionDenied(err error) bool {
+	return Code(err) == codes.PermissionDenied
+}
+
+// ResourceExhausted returns Error with codes.ResourceExhausted
+func ResourceExhausted(reason, format string, args ...any) *Error {
+	return Newf(codes.ResourceExhausted, reason, format, args...)
+}
+
+// IsResourceExhausted reports whether err is codes.ResourceExhausted
+func IsResourceExhausted(err error) bool {
+	return Code(err) == codes.ResourceExhausted
+}
+
+// FailedPrecondition returns Error with codes.FailedPrecondition
+func FailedPrecondition(reason, format string, args ...any) *Error {
+	return Newf(codes.FailedPrecondition, reason, format, args...)
+}
+
+// IsFailedPrecondition reports whether err is codes.FailedPrecondition
+func IsFailedPrecondition(err error) bool {
+	return Code(err) == codes.FailedPrecondition
+}
+
+// Aborted returns Error with codes.Aborted
+func Aborted(reason, format string, args ...any) *Error {
+	return Newf(codes.Aborted, reason, format, args...)
+}
+
+// IsAborted reports whether err is codes.Aborted
+func IsAborted(err error) bool {
+	return Code(err) == codes.Aborted
+}
+
+// OutOfRange returns Error with codes.OutOfRange
+func OutOfRange(reason, format string, args ...any) *Error {
+	return Newf(codes.OutOfRange, reason, format, args...)
+}
+
+// IsOutOfRange reports whether err is codes.OutOfRange
+func IsOutOfRange(err error) bool {
+	return Code(err) == codes.OutOfRange
+}
+
+// Unimplemented returns Error with codes.Unimplemented
+func Unimplemented(reason, format string, args ...any) *Error {
+	return Newf(codes.Unimplemented, reason, format, args...)
+}
+
+// IsUnimplemented reports whether err is codes.Unimplemented
+func IsUnimplemented(err error) bool {
+	return Code(err) == codes.Unimplemented
+}
+
+// Internal returns Error with codes.Internal
+func Internal(reason, format string, args ...any) *Error {
+	return Newf(codes.Internal, reason, format, args...)
+}
+
+// IsInternal reports whether err is codes.Internal
+func IsInternal(err error) bool {
+	return Code(err) == codes.Internal
+}
+
+// Unavailable returns Error with codes.Unavailable
+func Unavailable(reason, format string, args ...any) *Error {
+	return Newf(codes.Unavailable, reason, format, args...)
+}
+
+// IsUnavailable reports whether err is codes.Unavailable
+func IsUnavailable(err error) bool {
+	return Code(err) == codes.Unavailable
+}
+
+// DataLoss returns Error with codes.DataLoss
+func DataLoss(reason, format string, args ...any) *Error {
+	return Newf(codes.DataLoss, reason, format, args...)
+}
+
+// IsDataLoss reports whether err is codes.DataLoss
+func IsDataLoss(err error) bool {
+	return Code(err) == codes.DataLoss
+}
+
+// Unauthenticated returns Error with codes.Unauthenticated
+func Unauthenticated(reason, format string, args ...any) *Error {
+	return Newf(codes.Unauthenticated, reason, format, args...)
+}
+
+// IsUnauthenticated reports whether err is codes.Unauthenticated
+func IsUnauthenticated(err error) bool {
+	return Code(err) == codes.Unauthenticated
+}
@@ -1,23 +1,255 @@
 package errorx
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// UnknownReason is the reason of the error which is not an *Error
+const UnknownReason = ""
+
+// UnknownMessage is the message of the Unknown error converted from
+// a plain error, the plain error is kept as cause rather than exposed
+const UnknownMessage = "unknown error"
+
+// Error is the error which renders on both http and grpc,
+// the grpc status carries Reason and Metadata with errdetails.ErrorInfo
 type Error struct {
-	*status.Status
-	casue error
+	// Code is the grpc status code
+	Code codes.Code
+
+	// Status is the http status code, default is mapped from Code
+	Status int
+
+	// Reason is the machine-readable identifier of the error, eg: USER_NOT_FOUND
+	Reason string
+
+	// Message is the human-readable description
+	Message string
+
+	// Metadata is the additional structured details
+	Metadata map[string]string
+
+	cause error
+}
+
+// New returns Error with the code, reason and message
+func New(code codes.Code, reason, message string) *Error {
+	return &Error{
+		Code:    code,
+		Status:  HTTPStatusFromCode(code),
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// Newf returns Error with the formatted message
+func Newf(code codes.Code, reason, format string, args ...any) *Error {
+	return New(code, reason, sprintf(format, args...))
 }
 
 func (e *Error) Error() string {
-	return fmt.Sprintf("detail: %v", e.casue)
+	return fmt.Sprintf("error: code = %s reason = %s message = %s metadata = %v cause = %v",
+		e.Code, e.Reason, e.Message, e.Metadata, e.cause)
 }
 
-func New(code int, message string) *Error {
-	return &Error{
-		Status: status.New(codes.Canceled, message),
+// Unwrap returns the cause
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches each error in the chain with the same code and reason
+func (e *Error) Is(err error) bool {
+	if se := new(Error); errors.As(err, &se) {
+		return se.Code == e.Code && se.Reason == e.Reason
+	}
+	return false
+}
+
+// WithCause returns a copy of Error with the cause
+func (e *Error) WithCause(cause error) *Error {
+	err := e.clone()
+	err.cause = cause
+	return err
+}
+
+// WithMetadata returns a copy of Error with the metadata
+func (e *Error) WithMetadata(md map[string]string) *Error {
+	err := e.clone()
+	err.Metadata = md
+	return err
+}
+
+// WithStatus returns a copy of Error with the http status
+func (e *Error) WithStatus(status int) *Error {
+	err := e.clone()
+	err.Status = status
+	return err
+}
+
+// GRPCStatus returns the grpc status, it's called by grpc
+// when the Error returned from handler
+func (e *Error) GRPCStatus() *status.Status {
+	s := status.New(e.Code, e.Message)
+	if e.Reason == UnknownReason && len(e.Metadata) == 0 {
+		return s
+	}
+
+	ds, err := s.WithDetails(&errdetails.ErrorInfo{
+		Reason:   e.Reason,
+		Metadata: e.Metadata,
+	})
+	if err != nil {
+		return s
+	}
+	return ds
+}
+
+// HTTPStatus returns the http status code
+func (e *Error) HTTPStatus() int {
+	if e.Status == 0 {
+		return HTTPStatusFromCode(e.Code)
+	}
+	return e.Status
+}
+
+func (e *Error) clone() *Error {
+	err := *e
+	if e.Metadata != nil {
+		err.Metadata = make(map[string]string, len(e.Metadata))
+		for k, v := range e.Metadata {
+			err.Metadata[k] = v
+		}
+	}
+	return &err
+}
+
+// body is the http representation of Error
+type body struct {
+	XMLName  xml.Name          `json:"-" xml:"error"`
+	Code     int               `json:"code" xml:"code"`
+	Status   string            `json:"status" xml:"status"`
+	Reason   string            `json:"reason,omitempty" xml:"reason,omitempty"`
+	Message  string            `json:"message" xml:"message"`
+	Metadata map[string]string `json:"metadata,omitempty" xml:"-"`
+}
+
+func (e *Error) body() body {
+	return body{
+		Code:     e.HTTPStatus(),
+		Status:   CodeName(e.Code),
+		Reason:   e.Reason,
+		Message:  e.Message,
+		Metadata: e.Metadata,
+	}
+}
+
+func (e *Error) fromBody(b body) {
+	e.Code = CodeFromName(b.Status)
+	if b.Status == "" {
+		e.Code = CodeFromHTTPStatus(b.Code)
+	}
+	e.Status = b.Code
+	e.Reason = b.Reason
+	e.Message = b.Message
+	e.Metadata = b.Metadata
+}
+
+// MarshalJSON encode as {"code":404,"status":"NOT_FOUND","reason":"","message":"","metadata":{}}
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.body())
+}
+
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var b body
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	e.fromBody(b)
+	return nil
+}
+
+func (e *Error) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	return enc.Encode(e.body())
+}
+
+func (e *Error) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var b body
+	if err := dec.DecodeElement(&b, &start); err != nil {
+		return err
+	}
+	e.fromBody(b)
+	return nil
+}
+
+// FromError converts err to *Error, it supports *Error in the chain,
+// grpc status error and context errors, otherwise returns Unknown error
+// of UnknownMessage with err as cause. returns nil if err is nil
+func FromError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	if se := new(Error); errors.As(err, &se) {
+		return se
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return New(codes.Canceled, UnknownReason, context.Canceled.Error()).WithCause(err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return New(codes.DeadlineExceeded, UnknownReason, context.DeadlineExceeded.Error()).WithCause(err)
+	}
+
+	gs, ok := status.FromError(err)
+	if !ok {
+		return New(codes.Unknown, UnknownReason, UnknownMessage).WithCause(err)
+	}
+
+	e := New(gs.Code(), UnknownReason, gs.Message())
+	for _, detail := range gs.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			e.Reason = info.Reason
+			e.Metadata = info.Metadata
+			break
+		}
+	}
+	return e.WithCause(err)
+}
+
+// Code returns the grpc code of err, codes.OK if err is nil
+func Code(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	return FromError(err).Code
+}
+
+// Reason returns the reason of err
+func Reason(err error) string {
+	if err == nil {
+		return UnknownReason
+	}
+	return FromError(err).Reason
+}
+
+// HTTPStatus returns the http status code of err, 200 if err is nil
+func HTTPStatus(err error) int {
+	if err == nil {
+		return HTTPStatusFromCode(codes.OK)
+	}
+	return FromError(err).HTTPStatus()
+}
+
+func sprintf(format string, args ...any) string {
+	if len(args) == 0 {
+		return format
 	}
+	return fmt.Sprintf(format, args...)
 }
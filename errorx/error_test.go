@@ -0,0 +1,67 @@
+package errorx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestFromGRPCStatus(t *testing.T) {
+	origin := NotFound("USER_NOT_FOUND", "user %d not found", 1).
+		WithMetadata(map[string]string{"id": "1"})
+
+	e := FromError(origin.GRPCStatus().Err())
+	if e.Code != codes.NotFound || e.Reason != "USER_NOT_FOUND" || e.Metadata["id"] != "1" {
+		t.Fatalf("unexpected error: %v", e)
+	}
+	if e.HTTPStatus() != http.StatusNotFound {
+		t.Fatalf("http status = %d, want %d", e.HTTPStatus(), http.StatusNotFound)
+	}
+	if !errors.Is(fmt.Errorf("wrapped: %w", e), origin) {
+		t.Fatal("errors.Is should match the code and reason")
+	}
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{context.Canceled, codes.Canceled},
+		{fmt.Errorf("call: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{errors.New("boom"), codes.Unknown},
+		{fmt.Errorf("wrap: %w", PermissionDenied("DENIED", "no")), codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		if got := Code(tt.err); got != tt.code {
+			t.Errorf("Code(%v) = %s, want %s", tt.err, got, tt.code)
+		}
+	}
+
+	// the plain error is kept as cause rather than the message
+	boom := errors.New("select * from users: connection refused")
+	e := FromError(boom)
+	if e.Message != UnknownMessage || !errors.Is(e, boom) {
+		t.Fatalf("FromError = %v", e)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(InvalidArgument("BAD_NAME", "name is required"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := new(Error)
+	if err := json.Unmarshal(data, e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Code != codes.InvalidArgument || e.Status != http.StatusBadRequest || e.Reason != "BAD_NAME" {
+		t.Fatalf("unexpected error: %s => %v", data, e)
+	}
+}
@@ -0,0 +1,124 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v4.25.2
+// source: errorx/errors.proto
+
+package errorx
+
+import (
+	code "google.golang.org/genproto/googleapis/rpc/code"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var file_errorx_errors_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.EnumOptions)(nil),
+		ExtensionType: (*code.Code)(nil),
+		Field:         50108,
+		Name:          "pallas.errorx.default_code",
+		Tag:           "varint,50108,opt,name=default_code,enum=google.rpc.Code",
+		Filename:      "errorx/errors.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
+		ExtensionType: (*code.Code)(nil),
+		Field:         50109,
+		Name:          "pallas.errorx.code",
+		Tag:           "varint,50109,opt,name=code,enum=google.rpc.Code",
+		Filename:      "errorx/errors.proto",
+	},
+}
+
+// Extension fields to descriptorpb.EnumOptions.
+var (
+	// default_code is the code of the reasons which has no code option
+	//
+	// optional google.rpc.Code default_code = 50108;
+	E_DefaultCode = &file_errorx_errors_proto_extTypes[0]
+)
+
+// Extension fields to descriptorpb.EnumValueOptions.
+var (
+	// code is the code of the reason
+	//
+	// optional google.rpc.Code code = 50109;
+	E_Code = &file_errorx_errors_proto_extTypes[1]
+)
+
+var File_errorx_errors_proto protoreflect.FileDescriptor
+
+var file_errorx_errors_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x78, 0x2f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x70, 0x61, 0x6c, 0x6c, 0x61, 0x73, 0x2e, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x78, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x72,
+	0x70, 0x63, 0x2f, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x3a, 0x53, 0x0a,
+	0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x1c, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6e, 0x75, 0x6d, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xbc, 0x87, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x0b, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x43, 0x6f,
+	0x64, 0x65, 0x3a, 0x49, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x21, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75,
+	0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xbd, 0x87,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x42, 0x2c, 0x5a,
+	0x2a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x68, 0x61, 0x72,
+	0x6c, 0x69, 0x65, 0x67, 0x6f, 0x33, 0x2f, 0x70, 0x61, 0x6c, 0x6c, 0x61, 0x73, 0x2f, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x78, 0x3b, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x78, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var file_errorx_errors_proto_goTypes = []interface{}{
+	(*descriptorpb.EnumOptions)(nil),      // 0: google.protobuf.EnumOptions
+	(*descriptorpb.EnumValueOptions)(nil), // 1: google.protobuf.EnumValueOptions
+	(code.Code)(0),                        // 2: google.rpc.Code
+}
+var file_errorx_errors_proto_depIdxs = []int32{
+	0, // 0: pallas.errorx.default_code:extendee -> google.protobuf.EnumOptions
+	1, // 1: pallas.errorx.code:extendee -> google.protobuf.EnumValueOptions
+	2, // 2: pallas.errorx.default_code:type_name -> google.rpc.Code
+	2, // 3: pallas.errorx.code:type_name -> google.rpc.Code
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	2, // [2:4] is the sub-list for extension type_name
+	0, // [0:2] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_errorx_errors_proto_init() }
+func file_errorx_errors_proto_init() {
+	if File_errorx_errors_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_errorx_errors_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 2,
+			NumServices:   0,
+		},
+		GoTypes:           file_errorx_errors_proto_goTypes,
+		DependencyIndexes: file_errorx_errors_proto_depIdxs,
+		ExtensionInfos:    file_errorx_errors_proto_extTypes,
+	}.Build()
+	File_errorx_errors_proto = out.File
+	file_errorx_errors_proto_rawDesc = nil
+	file_errorx_errors_proto_goTypes = nil
+	file_errorx_errors_proto_depIdxs = nil
+}
@@ -0,0 +1,146 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v4.25.2
+// source: protos/errors.proto
+
+package pb
+
+import (
+	_ "github.com/charliego3/pallas/errorx"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ErrorReason int32
+
+const (
+	ErrorReason_UNKNOWN_ERROR ErrorReason = 0
+	// the user is not found by uname or email
+	ErrorReason_USER_NOT_FOUND ErrorReason = 1
+	// the uname or email is already registered
+	ErrorReason_USER_EXISTS ErrorReason = 2
+	// the password is incorrect
+	ErrorReason_PASSWORD_INCORRECT ErrorReason = 3
+)
+
+// Enum value maps for ErrorReason.
+var (
+	ErrorReason_name = map[int32]string{
+		0: "UNKNOWN_ERROR",
+		1: "USER_NOT_FOUND",
+		2: "USER_EXISTS",
+		3: "PASSWORD_INCORRECT",
+	}
+	ErrorReason_value = map[string]int32{
+		"UNKNOWN_ERROR":      0,
+		"USER_NOT_FOUND":     1,
+		"USER_EXISTS":        2,
+		"PASSWORD_INCORRECT": 3,
+	}
+)
+
+func (x ErrorReason) Enum() *ErrorReason {
+	p := new(ErrorReason)
+	*p = x
+	return p
+}
+
+func (x ErrorReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_errors_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorReason) Type() protoreflect.EnumType {
+	return &file_protos_errors_proto_enumTypes[0]
+}
+
+func (x ErrorReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorReason.Descriptor instead.
+func (ErrorReason) EnumDescriptor() ([]byte, []int) {
+	return file_protos_errors_proto_rawDescGZIP(), []int{0}
+}
+
+var File_protos_errors_proto protoreflect.FileDescriptor
+
+var file_protos_errors_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x1a, 0x13, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x78, 0x2f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2a, 0x75, 0x0a, 0x0b, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x11, 0x0a, 0x0d, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x0e, 0x55, 0x53, 0x45, 0x52, 0x5f, 0x4e, 0x4f, 0x54,
+	0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x01, 0x1a, 0x04, 0xe8, 0xbb, 0x18, 0x05, 0x12, 0x15,
+	0x0a, 0x0b, 0x55, 0x53, 0x45, 0x52, 0x5f, 0x45, 0x58, 0x49, 0x53, 0x54, 0x53, 0x10, 0x02, 0x1a,
+	0x04, 0xe8, 0xbb, 0x18, 0x06, 0x12, 0x1c, 0x0a, 0x12, 0x50, 0x41, 0x53, 0x53, 0x57, 0x4f, 0x52,
+	0x44, 0x5f, 0x49, 0x4e, 0x43, 0x4f, 0x52, 0x52, 0x45, 0x43, 0x54, 0x10, 0x03, 0x1a, 0x04, 0xe8,
+	0xbb, 0x18, 0x10, 0x1a, 0x04, 0xe0, 0xbb, 0x18, 0x0d, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x68, 0x61, 0x72, 0x6c, 0x69, 0x65, 0x67,
+	0x6f, 0x33, 0x2f, 0x70, 0x61, 0x6c, 0x6c, 0x61, 0x73, 0x2f, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_protos_errors_proto_rawDescOnce sync.Once
+	file_protos_errors_proto_rawDescData = file_protos_errors_proto_rawDesc
+)
+
+func file_protos_errors_proto_rawDescGZIP() []byte {
+	file_protos_errors_proto_rawDescOnce.Do(func() {
+		file_protos_errors_proto_rawDescData = protoimpl.X.CompressGZIP(file_protos_errors_proto_rawDescData)
+	})
+	return file_protos_errors_proto_rawDescData
+}
+
+var file_protos_errors_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_protos_errors_proto_goTypes = []interface{}{
+	(ErrorReason)(0), // 0: protos.ErrorReason
+}
+var file_protos_errors_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_protos_errors_proto_init() }
+func file_protos_errors_proto_init() {
+	if File_protos_errors_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_protos_errors_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_protos_errors_proto_goTypes,
+		DependencyIndexes: file_protos_errors_proto_depIdxs,
+		EnumInfos:         file_protos_errors_proto_enumTypes,
+	}.Build()
+	File_protos_errors_proto = out.File
+	file_protos_errors_proto_rawDesc = nil
+	file_protos_errors_proto_goTypes = nil
+	file_protos_errors_proto_depIdxs = nil
+}
@@ -26,9 +26,10 @@ func (UnimplementedGreeterDescServer) Desc() types.ServiceDesc {
 			HandlerType: nil,
 			Methods: []types.HttpMethodDesc{
 				{
-					Method:   "GET",
-					Template: "/sayHello",
-					Handler:  _Greeter_SayHello_GET_HTTP_Handler,
+					Method:    "GET",
+					Template:  "/sayHello",
+					Operation: "/protos.Greeter/SayHello",
+					Handler:   _Greeter_SayHello_GET_HTTP_Handler,
 				},
 			},
 		},
@@ -22,16 +22,39 @@ type GreeterHTTPServer interface {
 }
 
 func RegisterGreeterHTTPServer(s *httpx.Server, srv GreeterHTTPServer) {
-	s.HandleMethod("GET", "/sayHello", _Greeter_SayHello_GET_HTTP_Handler(srv.(types.Service)).(httpx.Handler))
+	s.HandleOperation("/protos.Greeter/SayHello", "GET", "/sayHello", _Greeter_SayHello_GET_HTTP_Handler(srv.(types.Service)).(httpx.Handler))
 }
 
 func _Greeter_SayHello_GET_HTTP_Handler(srv types.Service) any {
 	return httpx.Handler(func(ctx *httpx.Context) (any, error) {
 		req := new(HelloRequest)
-		if err := ctx.Bind(req); err != nil {
+		if err := ctx.BindQuery(req); err != nil {
+			return nil, err
+		}
+		if err := ctx.BindVars(req); err != nil {
 			return nil, err
 		}
 		ctx.Payload = req
 		return srv.(GreeterServer).SayHello(ctx, req)
 	})
 }
+
+type GreeterHTTPClient interface {
+	SayHello(ctx context.Context, in *HelloRequest, opts ...httpx.CallOption) (*HelloReply, error)
+}
+
+type GreeterHTTPClientImpl struct {
+	cc *httpx.Client
+}
+
+func NewGreeterHTTPClient(client *httpx.Client) GreeterHTTPClient {
+	return &GreeterHTTPClientImpl{client}
+}
+
+func (c *GreeterHTTPClientImpl) SayHello(ctx context.Context, in *HelloRequest, opts ...httpx.CallOption) (*HelloReply, error) {
+	out := new(HelloReply)
+	path := httpx.EncodeURL("/sayHello", in, "")
+	opts = append([]httpx.CallOption{httpx.Operation("/protos.Greeter/SayHello"), httpx.PathTemplate("/sayHello")}, opts...)
+	err := c.cc.Invoke(ctx, "GET", path, nil, out, opts...)
+	return out, err
+}
@@ -26,14 +26,16 @@ func (UnimplementedUserDescServer) Desc() types.ServiceDesc {
 			HandlerType: nil,
 			Methods: []types.HttpMethodDesc{
 				{
-					Method:   "POST",
-					Template: "/user/register",
-					Handler:  _User_Register_POST_HTTP_Handler,
+					Method:    "POST",
+					Template:  "/user/register",
+					Operation: "/protos.User/Register",
+					Handler:   _User_Register_POST_HTTP_Handler,
 				},
 				{
-					Method:   "POST",
-					Template: "/user/login",
-					Handler:  _User_Login_POST_HTTP_Handler,
+					Method:    "POST",
+					Template:  "/user/login",
+					Operation: "/protos.User/Login",
+					Handler:   _User_Login_POST_HTTP_Handler,
 				},
 			},
 		},
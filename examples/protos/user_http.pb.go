@@ -23,14 +23,17 @@ type UserHTTPServer interface {
 }
 
 func RegisterUserHTTPServer(s *httpx.Server, srv UserHTTPServer) {
-	s.HandleMethod("POST", "/user/register", _User_Register_POST_HTTP_Handler(srv.(types.Service)).(httpx.Handler))
-	s.HandleMethod("POST", "/user/login", _User_Login_POST_HTTP_Handler(srv.(types.Service)).(httpx.Handler))
+	s.HandleOperation("/protos.User/Register", "POST", "/user/register", _User_Register_POST_HTTP_Handler(srv.(types.Service)).(httpx.Handler))
+	s.HandleOperation("/protos.User/Login", "POST", "/user/login", _User_Login_POST_HTTP_Handler(srv.(types.Service)).(httpx.Handler))
 }
 
 func _User_Register_POST_HTTP_Handler(srv types.Service) any {
 	return httpx.Handler(func(ctx *httpx.Context) (any, error) {
 		req := new(RegisterRequest)
-		if err := ctx.Bind(req); err != nil {
+		if err := ctx.BindBody(req); err != nil {
+			return nil, err
+		}
+		if err := ctx.BindVars(req); err != nil {
 			return nil, err
 		}
 		ctx.Payload = req
@@ -41,10 +44,42 @@ func _User_Register_POST_HTTP_Handler(srv types.Service) any {
 func _User_Login_POST_HTTP_Handler(srv types.Service) any {
 	return httpx.Handler(func(ctx *httpx.Context) (any, error) {
 		req := new(LoginRequest)
-		if err := ctx.Bind(req); err != nil {
+		if err := ctx.BindBody(req); err != nil {
+			return nil, err
+		}
+		if err := ctx.BindVars(req); err != nil {
 			return nil, err
 		}
 		ctx.Payload = req
 		return srv.(UserServer).Login(ctx, req)
 	})
 }
+
+type UserHTTPClient interface {
+	Register(ctx context.Context, in *RegisterRequest, opts ...httpx.CallOption) (*LoginReply, error)
+	Login(ctx context.Context, in *LoginRequest, opts ...httpx.CallOption) (*LoginReply, error)
+}
+
+type UserHTTPClientImpl struct {
+	cc *httpx.Client
+}
+
+func NewUserHTTPClient(client *httpx.Client) UserHTTPClient {
+	return &UserHTTPClientImpl{client}
+}
+
+func (c *UserHTTPClientImpl) Register(ctx context.Context, in *RegisterRequest, opts ...httpx.CallOption) (*LoginReply, error) {
+	out := new(LoginReply)
+	path := httpx.EncodeURL("/user/register", in, "*")
+	opts = append([]httpx.CallOption{httpx.Operation("/protos.User/Register"), httpx.PathTemplate("/user/register")}, opts...)
+	err := c.cc.Invoke(ctx, "POST", path, in, out, opts...)
+	return out, err
+}
+
+func (c *UserHTTPClientImpl) Login(ctx context.Context, in *LoginRequest, opts ...httpx.CallOption) (*LoginReply, error) {
+	out := new(LoginReply)
+	path := httpx.EncodeURL("/user/login", in, "*")
+	opts = append([]httpx.CallOption{httpx.Operation("/protos.User/Login"), httpx.PathTemplate("/user/login")}, opts...)
+	err := c.cc.Invoke(ctx, "POST", path, in, out, opts...)
+	return out, err
+}
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/charliego3/pallas/examples/protos"
@@ -20,5 +19,5 @@ func (u *User) Register(ctx context.Context, in *pb.RegisterRequest) (*pb.LoginR
 }
 
 func (u *User) Login(ctx context.Context, in *pb.LoginRequest) (*pb.LoginReply, error) {
-	return nil, errors.New("balabala")
+	return nil, pb.ErrorUserNotFound("user %s not found", in.Uname)
 }
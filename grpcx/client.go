@@ -0,0 +1,141 @@
+package grpcx
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/grpcx/discovery"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/utility"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// Client is a grpc.ClientConn which runs the middleware chain on outgoing calls,
+// it can be passed to the generated NewXxxClient directly
+type Client struct {
+	*grpc.ClientConn
+	*clientOptions
+}
+
+// Dial creates a client connection to the target,
+// target using discovery:///service-name when WithDiscovery is specified
+func Dial(ctx context.Context, target string, opts ...utility.Option[Client]) (*Client, error) {
+	c := new(Client)
+	c.clientOptions = new(clientOptions)
+	c.logger = slog.Default()
+	utility.Apply(c, opts...)
+	dialOpts := []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(append(
+			[]grpc.UnaryClientInterceptor{c.unaryInterceptor},
+			c.unaryInters...,
+		)...),
+		grpc.WithChainStreamInterceptor(append(
+			[]grpc.StreamClientInterceptor{c.streamInterceptor},
+			c.streamInters...,
+		)...),
+	}
+	if c.tlsConfig != nil {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(c.tlsConfig)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	if c.discovery != nil {
+		dialOpts = append(dialOpts, grpc.WithResolvers(
+			discovery.NewBuilder(c.discovery, discovery.WithLogger(c.logger)),
+		))
+	}
+	if len(c.dialOpts) > 0 {
+		dialOpts = append(dialOpts, c.dialOpts...)
+	}
+
+	conn, err := grpc.DialContext(ctx, target, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	c.ClientConn = conn
+	return c, nil
+}
+
+// NewClient is Dial with background context
+func NewClient(target string, opts ...utility.Option[Client]) (*Client, error) {
+	return Dial(context.Background(), target, opts...)
+}
+
+func (c *Client) unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	gctx := middleware.NewGRPCClientContext(ctx, method, req)
+	m := middleware.Chain(c.middlewares...)
+	_, err := m(func(mctx *middleware.Context) (any, error) {
+		var header metadata.MD
+		ctx := metadata.NewOutgoingContext(mctx, metadata.MD(mctx.ReqHeader))
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Header(&header))...)
+		for k, v := range header {
+			mctx.ResHeader.Add(k, v...)
+		}
+		return reply, err
+	})(gctx)
+	if err != nil {
+		return errorx.FromError(err)
+	}
+	return nil
+}
+
+// streamInterceptor runs the middleware chain on creating the stream,
+// the messages are observed by the Stream hooks of Context
+func (c *Client) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	gctx := middleware.NewGRPCClientStreamContext(ctx, method, desc.ClientStreams, desc.ServerStreams)
+	m := middleware.Chain(c.middlewares...)
+	reply, err := m(func(mctx *middleware.Context) (any, error) {
+		ctx := metadata.NewOutgoingContext(mctx, metadata.MD(mctx.ReqHeader))
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, ctx: mctx}, nil
+	})(gctx)
+	if err != nil {
+		return nil, errorx.FromError(err)
+	}
+	return reply.(grpc.ClientStream), nil
+}
+
+// clientStream calls the Stream hooks on each message,
+// the response header is copied to the Context once received
+type clientStream struct {
+	grpc.ClientStream
+	ctx *middleware.Context
+}
+
+func (s *clientStream) Header() (metadata.MD, error) {
+	header, err := s.ClientStream.Header()
+	for k, v := range header {
+		s.ctx.ResHeader[k] = v
+	}
+	return header, err
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	if err := s.ctx.Stream.Sending(s.ctx, m); err != nil {
+		return errorx.FromError(err)
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	if err := s.ClientStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if err := s.ctx.Stream.Received(s.ctx, m); err != nil {
+		return errorx.FromError(err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package grpcx
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"time"
+
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/registry"
+	"github.com/charliego3/pallas/utility"
+	"google.golang.org/grpc"
+)
+
+type clientOptions struct {
+	dialOpts     []grpc.DialOption
+	unaryInters  []grpc.UnaryClientInterceptor
+	streamInters []grpc.StreamClientInterceptor
+	tlsConfig    *tls.Config
+	middlewares  []middleware.Middleware
+	timeout      time.Duration
+	discovery    registry.Discovery
+	logger       *slog.Logger
+}
+
+// WithClientMiddleware inject Middleware to the outgoing calls, the middlewares
+// run on creating the stream for streaming calls, see middleware.Stream
+func WithClientMiddleware(middlewares ...middleware.Middleware) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	})
+}
+
+// WithClientTimeout specify the default timeout of unary calls,
+// the call's own deadline takes precedence if it's earlier
+func WithClientTimeout(timeout time.Duration) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.timeout = timeout
+	})
+}
+
+// WithClientTLS dial with TLS credentials, default is insecure
+func WithClientTLS(config *tls.Config) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.tlsConfig = config
+	})
+}
+
+// WithDiscovery resolve discovery:///service-name target from the discovery
+func WithDiscovery(d registry.Discovery) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.discovery = d
+	})
+}
+
+func WithClientUnaryInterceptor(interceptors ...grpc.UnaryClientInterceptor) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.unaryInters = interceptors
+	})
+}
+
+func WithClientStreamInterceptor(interceptors ...grpc.StreamClientInterceptor) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.streamInters = interceptors
+	})
+}
+
+// WithDialOption inject grpc.DialOption to client
+func WithDialOption(opts ...grpc.DialOption) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.dialOpts = opts
+	})
+}
+
+func WithClientLogger(logger *slog.Logger) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.logger = logger
+	})
+}
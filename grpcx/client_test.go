@@ -0,0 +1,160 @@
+package grpcx
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charliego3/pallas/errorx"
+	pb "github.com/charliego3/pallas/examples/protos"
+	"github.com/charliego3/pallas/middleware"
+	"google.golang.org/grpc/codes"
+)
+
+func (echoGreeter) SayHello(ctx context.Context, req *pb.HelloRequest) (*pb.HelloReply, error) {
+	switch req.Name {
+	case "missing":
+		return nil, errorx.NotFound("USER_NOT_FOUND", "user %s not found", req.Name)
+	case "slow":
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+	return &pb.HelloReply{Message: "hello " + req.Name}, nil
+}
+
+// serveEcho runs the echoGreeter server which echoes the x-client
+// header as the x-server header, returns the address
+func serveEcho(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(WithMiddleware(func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			ctx.ResHeader.Set("x-server", ctx.ReqHeader.Get("x-client"))
+			return next(ctx)
+		}
+	}))
+	s.Listener = lis
+	s.RegisterService(new(echoGreeter))
+	go func() { _ = s.Run(context.Background()) }()
+	t.Cleanup(func() { _ = s.Shutdown(context.Background()) })
+	return lis.Addr().String()
+}
+
+// clientMiddleware sets the x-client header and records the x-server header
+func clientMiddleware(kinds chan<- middleware.Kind, header *atomic.Value) middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			kinds <- ctx.Kind
+			ctx.ReqHeader.Set("x-client", ctx.Operation)
+			reply, err := next(ctx)
+			if ctx.Stream == nil {
+				header.Store(ctx.ResHeader.Get("x-server"))
+			}
+			return reply, err
+		}
+	}
+}
+
+func TestClientUnary(t *testing.T) {
+	kinds := make(chan middleware.Kind, 10)
+	var header atomic.Value
+	client, err := Dial(context.Background(), serveEcho(t),
+		WithClientMiddleware(clientMiddleware(kinds, &header)),
+		WithClientTimeout(100*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	greeter := pb.NewGreeterClient(client)
+	reply, err := greeter.SayHello(context.Background(), &pb.HelloRequest{Name: "pallas"})
+	if err != nil || reply.Message != "hello pallas" {
+		t.Fatalf("SayHello = %v, %v", reply, err)
+	}
+	if kind := <-kinds; kind != middleware.KindGRPCClient {
+		t.Fatalf("kind = %s", kind)
+	}
+	if got := header.Load(); got != "/protos.Greeter/SayHello" {
+		t.Fatalf("x-server = %v", got)
+	}
+
+	_, err = greeter.SayHello(context.Background(), &pb.HelloRequest{Name: "missing"})
+	if !errorx.IsNotFound(err) || errorx.Reason(err) != "USER_NOT_FOUND" {
+		t.Fatalf("missing: %v", err)
+	}
+
+	_, err = greeter.SayHello(context.Background(), &pb.HelloRequest{Name: "slow"})
+	if errorx.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("slow: %v", err)
+	}
+}
+
+func TestClientStream(t *testing.T) {
+	kinds := make(chan middleware.Kind, 10)
+	var header atomic.Value
+	var sent, recv atomic.Int32
+	hooks := func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			if ctx.Stream == nil || !ctx.Stream.ClientStreams || !ctx.Stream.ServerStreams {
+				t.Errorf("unexpected stream: %+v", ctx.Stream)
+				return next(ctx)
+			}
+			ctx.Stream.OnSend(func(_ *middleware.Context, msg any) error {
+				if msg.(*pb.HelloRequest).Name == "denied" {
+					return errorx.PermissionDenied("DENIED", "denied")
+				}
+				sent.Add(1)
+				return nil
+			})
+			ctx.Stream.OnRecv(func(*middleware.Context, any) error {
+				recv.Add(1)
+				return nil
+			})
+			return next(ctx)
+		}
+	}
+	client, err := Dial(context.Background(), serveEcho(t),
+		WithClientMiddleware(clientMiddleware(kinds, &header), hooks),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	stream, err := pb.NewGreeterClient(client).SayHelloStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kind := <-kinds; kind != middleware.KindGRPCClient {
+		t.Fatalf("kind = %s", kind)
+	}
+	for _, name := range []string{"a", "b"} {
+		if err = stream.Send(&pb.HelloRequest{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = stream.Recv(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = stream.Send(&pb.HelloRequest{Name: "denied"}); !errorx.IsPermissionDenied(err) {
+		t.Fatalf("send denied: %v", err)
+	}
+	_ = stream.CloseSend()
+
+	md, _ := stream.Header()
+	if got := md.Get("x-server"); len(got) == 0 || got[0] != "/protos.Greeter/SayHelloStream" {
+		t.Fatalf("header = %v", md)
+	}
+	if sent.Load() != 2 || recv.Load() != 2 {
+		t.Fatalf("sent = %d, recv = %d", sent.Load(), recv.Load())
+	}
+}
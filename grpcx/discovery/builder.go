@@ -0,0 +1,66 @@
+package discovery
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/charliego3/pallas/registry"
+	"github.com/charliego3/pallas/utility"
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme is the resolver scheme, eg: discovery:///user-service
+const Scheme = "discovery"
+
+var _ resolver.Builder = (*Builder)(nil)
+
+// Builder is a grpc resolver.Builder which resolve
+// the service instances from registry.Discovery
+type Builder struct {
+	discovery registry.Discovery
+	logger    *slog.Logger
+}
+
+// NewBuilder returns resolver builder with discovery,
+// using grpc.WithResolvers(builder) when dial the target
+func NewBuilder(d registry.Discovery, opts ...utility.Option[Builder]) *Builder {
+	b := new(Builder)
+	b.discovery = d
+	b.logger = slog.Default()
+	utility.Apply(b, opts...)
+	return b
+}
+
+func WithLogger(logger *slog.Logger) utility.Option[Builder] {
+	return utility.OptionFunc[Builder](func(b *Builder) {
+		b.logger = logger
+	})
+}
+
+// Build creates a resolver that watches the service name of target endpoint
+func (b *Builder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	name := strings.TrimPrefix(target.Endpoint(), "/")
+	w, err := b.discovery.Watch(ctx, name)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	r := &discoveryResolver{
+		w:      w,
+		cc:     cc,
+		ctx:    ctx,
+		cancel: cancel,
+		name:   name,
+		logger: b.logger,
+	}
+	go r.watch()
+	return r, nil
+}
+
+// Scheme returns discovery
+func (*Builder) Scheme() string {
+	return Scheme
+}
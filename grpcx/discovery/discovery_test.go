@@ -0,0 +1,122 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/charliego3/pallas/registry"
+	"github.com/charliego3/pallas/registry/memory"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func serve(t *testing.T, id string) (*registry.ServiceInstance, *grpc.Server) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := grpc.NewServer()
+	hs := health.NewServer()
+	hs.SetServingStatus(id, grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(srv, hs)
+	go func() { _ = srv.Serve(lis) }()
+	return &registry.ServiceInstance{
+		ID:        id,
+		Name:      "health",
+		Endpoints: []string{"grpc://" + lis.Addr().String()},
+	}, srv
+}
+
+func check(ctx context.Context, client grpc_health_v1.HealthClient, id string) error {
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: id}, grpc.WaitForReady(true))
+	return err
+}
+
+func TestResolver(t *testing.T) {
+	r := memory.New()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	ins1, srv1 := serve(t, "ins1")
+	defer srv1.Stop()
+	_ = r.Register(ctx, ins1)
+
+	conn, err := grpc.Dial("discovery:///health",
+		grpc.WithResolvers(NewBuilder(r)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := grpc_health_v1.NewHealthClient(conn)
+	if err = check(ctx, client, "ins1"); err != nil {
+		t.Fatalf("check ins1: %v", err)
+	}
+
+	ins2, srv2 := serve(t, "ins2")
+	defer srv2.Stop()
+	_ = r.Register(ctx, ins2)
+	_ = r.UnRegister(ctx, ins1)
+	srv1.Stop()
+
+	for {
+		if err = check(ctx, client, "ins2"); err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("check ins2 after update: %v", err)
+		}
+		time.Sleep(time.Millisecond * 50)
+	}
+}
+
+func TestResolverNoEndpoint(t *testing.T) {
+	r := memory.New()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	conn, err := grpc.Dial("discovery:///health",
+		grpc.WithResolvers(NewBuilder(r)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// fails fast instead of waiting until the deadline
+	client := grpc_health_v1.NewHealthClient(conn)
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if status.Code(err) != codes.Unavailable || ctx.Err() != nil {
+		t.Fatalf("check without instance: %v", err)
+	}
+
+	ins, srv := serve(t, "ins")
+	defer srv.Stop()
+	_ = r.Register(ctx, ins)
+	if err = check(ctx, client, "ins"); err != nil {
+		t.Fatalf("check ins: %v", err)
+	}
+
+	// the stale address is cleared after all instances deregistered
+	_ = r.UnRegister(ctx, ins)
+	for {
+		_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "ins"})
+		if status.Code(err) == codes.Unavailable {
+			break
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("check after deregistered: %v", err)
+		}
+		time.Sleep(time.Millisecond * 50)
+	}
+}
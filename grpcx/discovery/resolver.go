@@ -0,0 +1,106 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/charliego3/pallas/registry"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/resolver"
+)
+
+type instanceKey struct{}
+
+// InstanceFromAddress returns the service instance which the address resolved from
+func InstanceFromAddress(addr resolver.Address) (*registry.ServiceInstance, bool) {
+	if addr.Attributes == nil {
+		return nil, false
+	}
+	ins, ok := addr.Attributes.Value(instanceKey{}).(*registry.ServiceInstance)
+	return ins, ok
+}
+
+type discoveryResolver struct {
+	w      registry.Watcher
+	cc     resolver.ClientConn
+	ctx    context.Context
+	cancel context.CancelFunc
+	name   string
+	logger *slog.Logger
+
+	// resolved is true once the state has been updated
+	resolved bool
+}
+
+// watch update the client conn state until resolver closed
+func (r *discoveryResolver) watch() {
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		default:
+		}
+
+		instances, err := r.w.Next()
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			r.logger.Error("[Discovery] watch failed", slog.String("service", r.name), slog.Any("err", err))
+			// keep the last state if any, avoid all requests failed
+			// when the registry is temporarily unavailable
+			if !r.resolved {
+				r.cc.ReportError(err)
+			}
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		r.update(instances)
+	}
+}
+
+func (r *discoveryResolver) update(instances []*registry.ServiceInstance) {
+	addrs := make([]resolver.Address, 0, len(instances))
+	for _, ins := range instances {
+		host, ok := ins.Endpoint("grpc")
+		if !ok {
+			continue
+		}
+		addrs = append(addrs, resolver.Address{
+			Addr:       host,
+			ServerName: ins.Name,
+			Attributes: attributes.New(instanceKey{}, ins),
+		})
+	}
+
+	// the stale addresses are cleared by the empty state once all
+	// instances are gone, the calls fail fast instead of waiting
+	if len(addrs) == 0 {
+		r.logger.Warn("[Discovery] no grpc endpoint found", slog.String("service", r.name))
+		if !r.resolved {
+			r.cc.ReportError(fmt.Errorf("discovery: no grpc endpoint of %s", r.name))
+			return
+		}
+	}
+
+	r.resolved = true
+	if err := r.cc.UpdateState(resolver.State{Addresses: addrs}); err != nil {
+		r.logger.Error("[Discovery] update state failed", slog.String("service", r.name), slog.Any("err", err))
+	}
+}
+
+func (r *discoveryResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (r *discoveryResolver) Close() {
+	r.cancel()
+	if err := r.w.Stop(); err != nil {
+		r.logger.Error("[Discovery] stop watcher failed", slog.String("service", r.name), slog.Any("err", err))
+	}
+}
@@ -2,23 +2,88 @@ package grpcx
 
 import (
 	"context"
+	"github.com/charliego3/pallas/errorx"
 	"github.com/charliego3/pallas/middleware"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func (s *Server) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	gctx := middleware.NewGRPCContext(ctx, info.FullMethod, req)
-	m := middleware.Chain(s.middlewares...)
-	return m(func(mctx *middleware.Context) (any, error) {
-		reply, err := handler(mctx, req)
-		_ = grpc.SetHeader(ctx, metadata.MD(gctx.ResHeader))
-		return reply, err
+	m := middleware.Chain(s.recoverer.Middleware(), middleware.Chain(s.middlewares...))
+	reply, err := m(func(mctx *middleware.Context) (any, error) {
+		return handler(mctx, req)
 	})(gctx)
+	// the header is set even if the call is rejected by middlewares
+	_ = grpc.SetHeader(ctx, metadata.MD(gctx.ResHeader))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return reply, nil
+}
+
+// toStatusError keeps the grpc status error as is,
+// otherwise converts to errorx.Error so that the code is mapped
+func toStatusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return errorx.FromError(err)
 }
 
 func (s *Server) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	grpc.MethodFromServerStream(ss)
-	metadata.FromIncomingContext(ss.Context())
-	return handler(srv, ss)
+	gctx := middleware.NewGRPCStreamContext(ss.Context(), info.FullMethod, info.IsClientStream, info.IsServerStream)
+	m := middleware.Chain(s.recoverer.Middleware(), middleware.Chain(s.middlewares...))
+	_, err := m(func(mctx *middleware.Context) (any, error) {
+		stream := &serverStream{ServerStream: ss, ctx: mctx}
+		err := handler(srv, stream)
+		stream.sendHeader()
+		return nil, err
+	})(gctx)
+	if err != nil {
+		return toStatusError(err)
+	}
+	return nil
+}
+
+// serverStream calls the Stream hooks on each message,
+// the Context is the middleware Context
+type serverStream struct {
+	grpc.ServerStream
+	ctx        *middleware.Context
+	headerSent bool
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if err := s.ctx.Stream.Received(s.ctx, m); err != nil {
+		return toStatusError(err)
+	}
+	return nil
+}
+
+func (s *serverStream) SendMsg(m any) error {
+	if err := s.ctx.Stream.Sending(s.ctx, m); err != nil {
+		return toStatusError(err)
+	}
+	s.sendHeader()
+	return s.ServerStream.SendMsg(m)
+}
+
+// sendHeader sets the response header of middlewares before the first message
+func (s *serverStream) sendHeader() {
+	if s.headerSent {
+		return
+	}
+	s.headerSent = true
+	if len(s.ctx.ResHeader) > 0 {
+		_ = s.ServerStream.SetHeader(metadata.MD(s.ctx.ResHeader))
+	}
 }
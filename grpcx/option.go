@@ -6,6 +6,7 @@ import (
 	"net"
 
 	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/middleware/recovery"
 	"github.com/charliego3/pallas/utility"
 
 	"google.golang.org/grpc"
@@ -18,6 +19,13 @@ type options struct {
 	tlsConfig     *tls.Config
 	middlewares   []middleware.Middleware
 	disableHealth bool
+
+	// managedHealth leaves the serving status to SetServingStatus,
+	// the services are NOT_SERVING until set
+	managedHealth bool
+
+	// recoverer recovers the panic of handler and middlewares
+	recoverer *recovery.Recoverer
 }
 
 func WithMiddleware(middlewares ...middleware.Middleware) utility.Option[Server] {
@@ -26,12 +34,28 @@ func WithMiddleware(middlewares ...middleware.Middleware) utility.Option[Server]
 	})
 }
 
+// WithRecovery customize the recovery of panic, it's always the
+// outermost middleware, the panic converts to errorx Internal by default
+func WithRecovery(opts ...utility.Option[recovery.Recoverer]) utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.recoverer = recovery.New(opts...)
+	})
+}
+
 func DisableHealth() utility.Option[Server] {
 	return utility.OptionFunc[Server](func(s *Server) {
 		s.disableHealth = true
 	})
 }
 
+// WithManagedHealth leaves the health status to SetServingStatus, the server
+// and services are NOT_SERVING until set rather than SERVING once Run
+func WithManagedHealth() utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.managedHealth = true
+	})
+}
+
 func WithUnaryInterceptor(interceptors ...grpc.UnaryServerInterceptor) utility.Option[Server] {
 	return utility.OptionFunc[Server](func(s *Server) {
 		s.unaryInters = interceptors
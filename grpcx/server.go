@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/charliego3/pallas/middleware/recovery"
 	"github.com/charliego3/pallas/types"
 	"github.com/charliego3/pallas/utility"
 	"github.com/pkg/errors"
@@ -32,6 +33,7 @@ type Server struct {
 func NewServer(opts ...utility.Option[Server]) *Server {
 	s := new(Server)
 	s.options = new(options)
+	s.recoverer = recovery.New()
 	s.BaseServer = types.NewBaseServer()
 	s.health = health.NewServer()
 	utility.Apply(s, opts...)
@@ -52,6 +54,9 @@ func NewServer(opts ...utility.Option[Server]) *Server {
 		grpcOpts = append(grpcOpts, s.serverOption...)
 	}
 	s.server = grpc.NewServer(grpcOpts...)
+	if s.managedHealth {
+		s.SetServingStatus("", false)
+	}
 	if !s.disableHealth {
 		grpc_health_v1.RegisterHealthServer(s.server, s.health)
 	}
@@ -64,22 +69,61 @@ func (g *Server) RegisterService(services ...types.Service) {
 	for _, srv := range services {
 		desc := srv.Desc()
 		g.server.RegisterService(&desc.Grpc, srv)
+		if g.managedHealth {
+			g.SetServingStatus(desc.Grpc.ServiceName, false)
+		}
 	}
 }
 
+// GetServiceInfo returns the registered services keyed by service name
+func (g *Server) GetServiceInfo() map[string]grpc.ServiceInfo {
+	return g.server.GetServiceInfo()
+}
+
 func (g *Server) Run(ctx context.Context) error {
 	if g.Listener == nil {
 		return NoListener
 	}
 
 	g.ctx = ctx
-	g.health.Resume()
+	if !g.managedHealth {
+		g.health.Resume()
+	}
 	g.Logger.Info("[gRPC] listening on", slog.String("address", g.Listener.Addr().String()))
 	return g.server.Serve(g.Listener)
 }
 
-func (g *Server) Shutdown(context.Context) error {
+// SetServingStatus sets the health status of service,
+// the empty service is the overall status of server
+func (g *Server) SetServingStatus(service string, serving bool) {
+	status := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = grpc_health_v1.HealthCheckResponse_SERVING
+	}
+	g.health.SetServingStatus(service, status)
+}
+
+// Drain sets all services to NOT_SERVING, the clients
+// watching health check stop sending new requests
+func (g *Server) Drain() {
 	g.health.Shutdown()
-	g.server.GracefulStop()
-	return nil
+}
+
+// Shutdown stops the server gracefully, the in-flight
+// requests are aborted if ctx is done before they finish
+func (g *Server) Shutdown(ctx context.Context) error {
+	g.Drain()
+	done := make(chan struct{})
+	go func() {
+		g.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		g.server.Stop()
+		return ctx.Err()
+	}
 }
@@ -0,0 +1,108 @@
+package grpcx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	pb "github.com/charliego3/pallas/examples/protos"
+	"github.com/charliego3/pallas/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type echoGreeter struct {
+	pb.UnimplementedGreeterDescServer
+}
+
+func (echoGreeter) SayHelloStream(stream pb.Greeter_SayHelloStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = stream.Send(&pb.HelloReply{Message: "hello " + req.Name}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestStreamMiddleware(t *testing.T) {
+	var recv, sent atomic.Int32
+	mw := func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			if ctx.Stream == nil || !ctx.Stream.ClientStreams || !ctx.Stream.ServerStreams {
+				t.Errorf("unexpected stream: %+v", ctx.Stream)
+				return next(ctx)
+			}
+			ctx.ResHeader.Set("x-stream", ctx.Path)
+			ctx.Stream.OnRecv(func(_ *middleware.Context, msg any) error {
+				if msg.(*pb.HelloRequest).Name == "denied" {
+					return status.Error(codes.PermissionDenied, "denied")
+				}
+				recv.Add(1)
+				return nil
+			})
+			ctx.Stream.OnSend(func(*middleware.Context, any) error {
+				sent.Add(1)
+				return nil
+			})
+			return next(ctx)
+		}
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(WithMiddleware(mw))
+	s.Listener = lis
+	s.RegisterService(new(echoGreeter))
+	go func() { _ = s.Run(context.Background()) }()
+	defer s.Shutdown(context.Background())
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewGreeterClient(conn).SayHelloStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b", "denied"} {
+		if err = stream.Send(&pb.HelloRequest{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = stream.CloseSend()
+
+	var replies int
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+		replies++
+	}
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("err = %v, want PermissionDenied", err)
+	}
+	if replies != 2 || recv.Load() != 2 || sent.Load() != 2 {
+		t.Fatalf("replies = %d, recv = %d, sent = %d", replies, recv.Load(), sent.Load())
+	}
+
+	header, _ := stream.Header()
+	if got := header.Get("x-stream"); len(got) == 0 || got[0] != "/protos.Greeter/SayHelloStream" {
+		t.Fatalf("header = %v", metadata.MD(header))
+	}
+}
@@ -0,0 +1,51 @@
+package pallas
+
+import (
+	"context"
+	"time"
+)
+
+// healthWatcher performs the readiness checks periodically and
+// updates the grpc health status of each registered service
+type healthWatcher struct {
+	app     *Application
+	trigger chan struct{}
+}
+
+func newHealthWatcher(app *Application) *healthWatcher {
+	return &healthWatcher{app: app, trigger: make(chan struct{}, 1)}
+}
+
+// notify updates the status immediately, eg: the Application is ready
+func (w *healthWatcher) notify() {
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (w *healthWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.app.healthInterval)
+	defer ticker.Stop()
+	for {
+		w.update(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-w.trigger:
+		}
+	}
+}
+
+func (w *healthWatcher) update(ctx context.Context) {
+	report := w.app.health.Ready(ctx)
+	for service := range w.app.grpc.GetServiceInfo() {
+		w.app.grpc.SetServingStatus(service, report.Serving(service))
+	}
+	w.app.grpc.SetServingStatus("", report.Serving(""))
+}
+
+func (w *healthWatcher) Stop(context.Context) error {
+	return nil
+}
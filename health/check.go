@@ -0,0 +1,164 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/charliego3/pallas/utility"
+	"github.com/redis/go-redis/v9"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	ev3 "go.etcd.io/etcd/client/v3"
+)
+
+// DefaultTimeout is the timeout of Check which not specified
+const DefaultTimeout = 5 * time.Second
+
+// CheckFunc returns nil if the dependency is healthy
+type CheckFunc func(context.Context) error
+
+// Check is a named health check
+type Check struct {
+	name string
+	fn   CheckFunc
+
+	// timeout of each check, default is DefaultTimeout
+	timeout time.Duration
+
+	// ttl caches the result, the check is performed on each request if 0
+	ttl time.Duration
+
+	// liveness is true if the check affects the liveness,
+	// all the checks affect the readiness
+	liveness bool
+
+	// services are the grpc service names which affected by this check,
+	// empty means all services
+	services []string
+
+	mu      sync.Mutex
+	result  Result
+	checked time.Time
+}
+
+// WithTimeout specifies the timeout of each check
+func WithTimeout(timeout time.Duration) utility.Option[Check] {
+	return utility.OptionFunc[Check](func(c *Check) {
+		c.timeout = timeout
+	})
+}
+
+// WithCacheTTL caches the result for ttl, it avoids
+// overwhelming the dependency by the frequent probes
+func WithCacheTTL(ttl time.Duration) utility.Option[Check] {
+	return utility.OptionFunc[Check](func(c *Check) {
+		c.ttl = ttl
+	})
+}
+
+// WithLiveness reports the check failure on liveness, the process
+// will be restarted by orchestrator, it's only for the unrecoverable failure
+func WithLiveness() utility.Option[Check] {
+	return utility.OptionFunc[Check](func(c *Check) {
+		c.liveness = true
+	})
+}
+
+// WithServices limits the check to the grpc services
+func WithServices(services ...string) utility.Option[Check] {
+	return utility.OptionFunc[Check](func(c *Check) {
+		c.services = services
+	})
+}
+
+// NewCheck returns the named check
+func NewCheck(name string, fn CheckFunc, opts ...utility.Option[Check]) *Check {
+	c := &Check{name: name, fn: fn, timeout: DefaultTimeout}
+	utility.Apply(c, opts...)
+	return c
+}
+
+// Name returns the check name
+func (c *Check) Name() string {
+	return c.name
+}
+
+// affects reports whether the check affects the grpc service
+func (c *Check) affects(service string) bool {
+	if len(c.services) == 0 || service == "" {
+		return true
+	}
+	for _, s := range c.services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// run performs the check or returns the cached result
+func (c *Check) run(ctx context.Context) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ttl > 0 && !c.checked.IsZero() && time.Since(c.checked) < c.ttl {
+		result := c.result
+		result.Cached = true
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	begin := time.Now()
+	errC := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errC <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		errC <- c.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errC:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	c.result = Result{Name: c.name, Status: StatusUp, Duration: Duration(time.Since(begin))}
+	if err != nil {
+		c.result.Status = StatusDown
+		c.result.Error = err.Error()
+	}
+	c.checked = time.Now()
+	return c.result
+}
+
+// SQL returns CheckFunc which pings the database
+func SQL(db *sql.DB) CheckFunc {
+	return db.PingContext
+}
+
+// Etcd returns CheckFunc which reads a key from etcd cluster,
+// the permission denied is healthy because the cluster responded
+func Etcd(client *ev3.Client) CheckFunc {
+	return func(ctx context.Context) error {
+		_, err := client.Get(ctx, "health")
+		if err == nil || errors.Is(err, rpctypes.ErrPermissionDenied) {
+			return nil
+		}
+		return err
+	}
+}
+
+// Redis returns CheckFunc which pings the redis server
+func Redis(client redis.UniversalClient) CheckFunc {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}
+}
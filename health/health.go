@@ -0,0 +1,192 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charliego3/pallas/utility"
+)
+
+const (
+	LivezURI  = "/livez"
+	ReadyzURI = "/readyz"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "UP"
+	StatusDown Status = "DOWN"
+)
+
+// Duration is time.Duration which is encoded as string, eg: 1.5ms
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Result is the result of a Check
+type Result struct {
+	Name     string   `json:"name"`
+	Status   Status   `json:"status"`
+	Error    string   `json:"error,omitempty"`
+	Duration Duration `json:"duration"`
+	Cached   bool     `json:"cached,omitempty"`
+}
+
+// Report is the aggregated results, the status is
+// UP only if all the checks are UP and not draining
+type Report struct {
+	Status Status   `json:"status"`
+	Reason string   `json:"reason,omitempty"`
+	Checks []Result `json:"checks"`
+
+	checks []*Check
+}
+
+// Serving reports whether the checks affect the grpc service are UP,
+// the empty service means the overall status
+func (r Report) Serving(service string) bool {
+	if r.Reason != "" {
+		return false
+	}
+	for i, c := range r.checks {
+		if c.affects(service) && r.Checks[i].Status != StatusUp {
+			return false
+		}
+	}
+	return true
+}
+
+// Health holds the checks, the readiness fails until Resume
+// is called and after Drain is called
+type Health struct {
+	mu      sync.RWMutex
+	checks  []*Check
+	serving atomic.Bool
+}
+
+// New returns Health which is not serving
+func New() *Health {
+	return new(Health)
+}
+
+// Register add the named check, the check affects the readiness
+// and it affects the liveness if WithLiveness specified
+//
+//	h.Register("redis", func(ctx context.Context) error {
+//		return rdb.Ping(ctx).Err()
+//	}, health.WithTimeout(time.Second), health.WithCacheTTL(time.Second*3))
+func (h *Health) Register(name string, fn CheckFunc, opts ...utility.Option[Check]) {
+	h.Add(NewCheck(name, fn, opts...))
+}
+
+// Add the checks to Health
+func (h *Health) Add(checks ...*Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, checks...)
+}
+
+// Resume marks the server is ready to serve
+func (h *Health) Resume() {
+	h.serving.Store(true)
+}
+
+// Drain marks the server is draining, the readiness fails
+func (h *Health) Drain() {
+	h.serving.Store(false)
+}
+
+// Live performs the liveness checks
+func (h *Health) Live(ctx context.Context) Report {
+	return h.report(ctx, func(c *Check) bool {
+		return c.liveness
+	}, "")
+}
+
+// Ready performs all the checks, it's DOWN if not serving
+func (h *Health) Ready(ctx context.Context) Report {
+	reason := ""
+	if !h.serving.Load() {
+		reason = "not serving"
+	}
+	return h.report(ctx, func(*Check) bool {
+		return true
+	}, reason)
+}
+
+// report performs the matched checks concurrently
+func (h *Health) report(ctx context.Context, match func(*Check) bool, reason string) Report {
+	h.mu.RLock()
+	var checks []*Check
+	for _, c := range h.checks {
+		if match(c) {
+			checks = append(checks, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	r := Report{Status: StatusUp, Reason: reason, Checks: make([]Result, len(checks)), checks: checks}
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *Check) {
+			defer wg.Done()
+			r.Checks[i] = c.run(ctx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	if !r.Serving("") {
+		r.Status = StatusDown
+	}
+	return r
+}
+
+// Err returns error if the report is DOWN
+func (r Report) Err() error {
+	if r.Status == StatusUp {
+		return nil
+	}
+	if r.Reason != "" {
+		return errors.New(r.Reason)
+	}
+	for _, c := range r.Checks {
+		if c.Status != StatusUp {
+			return errors.New(c.Name + ": " + c.Error)
+		}
+	}
+	return errors.New(string(r.Status))
+}
+
+// LivezHandler responds the liveness report
+func (h *Health) LivezHandler() http.Handler {
+	return handler(h.Live)
+}
+
+// ReadyzHandler responds the readiness report
+func (h *Health) ReadyzHandler() http.Handler {
+	return handler(h.Ready)
+}
+
+// handler responds the report in json, 503 Service Unavailable if it's DOWN
+func handler(check func(context.Context) Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := check(r.Context())
+		code := http.StatusOK
+		if report.Status != StatusUp {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
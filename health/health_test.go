@@ -0,0 +1,69 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestReport(t *testing.T) {
+	calls := 0
+	h := New()
+	h.Register("redis", func(context.Context) error {
+		calls++
+		return nil
+	}, WithCacheTTL(time.Minute), WithLiveness())
+	h.Register("etcd", func(context.Context) error {
+		return errors.New("connection refused")
+	}, WithServices("protos.User"))
+	h.Register("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, WithTimeout(10*time.Millisecond), WithServices("protos.Book"))
+
+	if r := h.Ready(context.Background()); r.Status != StatusDown || r.Reason == "" {
+		t.Fatalf("ready before resume: %+v", r)
+	}
+
+	h.Resume()
+	r := h.Ready(context.Background())
+	if r.Status != StatusDown || r.Checks[2].Error != context.DeadlineExceeded.Error() {
+		t.Fatalf("ready: %+v", r)
+	}
+	if r.Serving("protos.User") || r.Serving("protos.Book") || !r.Serving("protos.Greeter") {
+		t.Fatalf("unexpected service status: %+v", r)
+	}
+
+	live := h.Live(context.Background())
+	if live.Status != StatusUp || len(live.Checks) != 1 || !live.Checks[0].Cached || calls != 1 {
+		t.Fatalf("live: %+v, calls: %d", live, calls)
+	}
+
+	w := httptest.NewRecorder()
+	h.ReadyzHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, ReadyzURI, nil))
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), `"error":"connection refused"`) {
+		t.Fatalf("readyz = %d %s", w.Code, w.Body)
+	}
+}
+
+func TestRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer client.Close()
+
+	check := NewCheck("redis", Redis(client), WithTimeout(time.Second))
+	if r := check.run(context.Background()); r.Status != StatusUp {
+		t.Fatalf("redis up: %+v", r)
+	}
+	mr.Close()
+	if r := check.run(context.Background()); r.Status != StatusDown || r.Error == "" {
+		t.Fatalf("redis down: %+v", r)
+	}
+}
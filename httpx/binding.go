@@ -0,0 +1,213 @@
+package httpx
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// errFieldNotFound is returned when the field path is not found in message
+var errFieldNotFound = errors.New("field not found")
+
+// bindValues decode the values to v, proto.Message is populated
+// by field path, otherwise using gorilla/schema.
+// the unknown keys are ignored if ignoreUnknown is true
+func bindValues(v any, values url.Values, ignoreUnknown bool) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return valuesDecoder.Decode(v, values)
+	}
+
+	for k, vals := range values {
+		err := populateField(msg, k, vals)
+		if ignoreUnknown && errors.Is(err, errFieldNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// populateField set values to the dotted field path of msg,
+// the intermediate messages are allocated if absent,
+// each name of path is matched by proto name or json name,
+// the map entry is set by the key in brackets, eg: labels[env]
+func populateField(msg proto.Message, fieldPath string, values []string) error {
+	m := msg.ProtoReflect()
+	path, mapKey, isEntry := cutMapKey(fieldPath)
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		fd := lookupField(m.Descriptor(), name)
+		if fd == nil {
+			return fmt.Errorf("%w: %q in %s", errFieldNotFound, fieldPath, msg.ProtoReflect().Descriptor().FullName())
+		}
+
+		if i < len(names)-1 {
+			if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+				return fmt.Errorf("field %q is not a message in %s", name, fieldPath)
+			}
+			m = m.Mutable(fd).Message()
+			continue
+		}
+
+		if isEntry {
+			return setMapValue(m, fd, mapKey, values)
+		}
+		return setValues(m, fd, values)
+	}
+	return nil
+}
+
+func lookupField(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	fields := md.Fields()
+	if fd := fields.ByName(protoreflect.Name(name)); fd != nil {
+		return fd
+	}
+	return fields.ByJSONName(name)
+}
+
+func setValues(m protoreflect.Message, fd protoreflect.FieldDescriptor, values []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	switch {
+	case fd.IsMap():
+		return fmt.Errorf("map field %q must be set by key, eg: %s[key]", fd.Name(), fd.Name())
+	case fd.IsList():
+		list := m.Mutable(fd).List()
+		for _, value := range values {
+			v, err := parseValue(m, fd, value)
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+		}
+		return nil
+	default:
+		v, err := parseValue(m, fd, values[len(values)-1])
+		if err != nil {
+			return err
+		}
+		m.Set(fd, v)
+		return nil
+	}
+}
+
+// cutMapKey returns the field path and the key of map entry, eg: meta.labels[env]
+func cutMapKey(fieldPath string) (path, key string, ok bool) {
+	path, key, ok = strings.Cut(fieldPath, "[")
+	if !ok || !strings.HasSuffix(key, "]") {
+		return fieldPath, "", false
+	}
+	return path, key[:len(key)-1], true
+}
+
+// setMapValue set the last value to the map entry of key
+func setMapValue(m protoreflect.Message, fd protoreflect.FieldDescriptor, key string, values []string) error {
+	if !fd.IsMap() {
+		return fmt.Errorf("field %q is not a map", fd.Name())
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	k, err := parseValue(m, fd.MapKey(), key)
+	if err != nil {
+		return err
+	}
+	mp := m.Mutable(fd).Map()
+	var v protoreflect.Value
+	if fd.MapValue().Kind() == protoreflect.MessageKind {
+		v = mp.NewValue()
+		err = parseWellKnown(fd.MapValue(), v.Message(), values[len(values)-1])
+	} else {
+		v, err = parseValue(m, fd.MapValue(), values[len(values)-1])
+	}
+	if err != nil {
+		return err
+	}
+	mp.Set(k.MapKey(), v)
+	return nil
+}
+
+// parseValue parse the singular value of the field kind
+func parseValue(m protoreflect.Message, fd protoreflect.FieldDescriptor, value string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(value)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(value, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(value, 10, 64)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(value, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(v)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(value, 10, 64)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(value, 32)
+		return protoreflect.ValueOfFloat32(float32(v)), err
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(value, 64)
+		return protoreflect.ValueOfFloat64(v), err
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(value), nil
+	case protoreflect.BytesKind:
+		v, err := base64.URLEncoding.DecodeString(value)
+		if err != nil {
+			v, err = base64.StdEncoding.DecodeString(value)
+		}
+		return protoreflect.ValueOfBytes(v), err
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(value)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		v, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid value %q for enum %s", value, fd.Enum().FullName())
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), nil
+	case protoreflect.MessageKind:
+		msg := newMessage(m, fd)
+		if err := parseWellKnown(fd, msg, value); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(msg), nil
+	default:
+		return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s of %q", fd.Kind(), fd.Name())
+	}
+}
+
+// parseWellKnown unmarshal the json value of the well-known type to msg
+func parseWellKnown(fd protoreflect.FieldDescriptor, msg protoreflect.Message, value string) error {
+	if !isWellKnown(fd.Message()) {
+		return fmt.Errorf("message field %q must be set by sub fields", fd.Name())
+	}
+	if err := protojson.Unmarshal([]byte(strconv.Quote(value)), msg.Interface()); err != nil {
+		// wrappers of number and bool are not quoted in json
+		return protojson.Unmarshal([]byte(value), msg.Interface())
+	}
+	return nil
+}
+
+// newMessage returns an empty message of the field type
+func newMessage(m protoreflect.Message, fd protoreflect.FieldDescriptor) protoreflect.Message {
+	if fd.IsList() {
+		return m.Mutable(fd).List().NewElement().Message()
+	}
+	return m.NewField(fd).Message()
+}
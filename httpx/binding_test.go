@@ -0,0 +1,91 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charliego3/pallas/testdata/testpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestBindValues(t *testing.T) {
+	detail, err := anypb.New(&testpb.Nested{Value: "detail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		query   string
+		want    *testpb.Message
+		wantErr bool
+	}{
+		{"proto name", "name=a&created_at=2024-01-02T03:04:05Z&nested.value=v", &testpb.Message{
+			Name:      "a",
+			CreatedAt: timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+			Nested:    &testpb.Nested{Value: "v"},
+		}, false},
+		{"json name", "createdAt=2024-01-02T03:04:05Z", &testpb.Message{
+			CreatedAt: timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		}, false},
+		{"int64 as string", "id=9007199254740993&count=18446744073709551615", &testpb.Message{Id: 9007199254740993, Count: 18446744073709551615}, false},
+		{"enum by name", "kind=KIND_ADMIN&nested.kinds=KIND_USER&nested.kinds=2", &testpb.Message{
+			Kind:   testpb.Kind_KIND_ADMIN,
+			Nested: &testpb.Nested{Kinds: []testpb.Kind{testpb.Kind_KIND_USER, testpb.Kind_KIND_ADMIN}},
+		}, false},
+		{"duration", "ttl=1.5s", &testpb.Message{Ttl: durationpb.New(1500 * time.Millisecond)}, false},
+		{"wrappers", "nickname=nick&score=7&enabled=false", &testpb.Message{
+			Nickname: wrapperspb.String("nick"),
+			Score:    wrapperspb.Int64(7),
+			Enabled:  wrapperspb.Bool(false),
+		}, false},
+		{"any", "detail=" + url.QueryEscape(`{"@type":"type.googleapis.com/pallas.testpb.Nested","value":"detail"}`), &testpb.Message{Detail: detail}, false},
+		{"oneof", "phone=10086", &testpb.Message{Contact: &testpb.Message_Phone{Phone: 10086}}, false},
+		{"map", "labels[env]=prod&quotas[cpu]=2", &testpb.Message{
+			Labels: map[string]string{"env": "prod"},
+			Quotas: map[string]int64{"cpu": 2},
+		}, false},
+		{"repeated and bytes", "tags=x&tags=y&data=_w==", &testpb.Message{Tags: []string{"x", "y"}, Data: []byte{0xff}}, false},
+		{"unknown ignored", "unknown=1&name=a", &testpb.Message{Name: "a"}, false},
+		{"invalid int64", "id=abc", nil, true},
+		{"invalid enum", "kind=KIND_ROOT", nil, true},
+		{"invalid timestamp", "created_at=yesterday", nil, true},
+		{"map without key", "labels=prod", nil, true},
+		{"message without sub field", "nested=v", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, method := range []string{http.MethodGet, http.MethodPost} {
+				var r *http.Request
+				if method == http.MethodGet {
+					r = httptest.NewRequest(method, "/?"+tt.query, nil)
+				} else {
+					r = httptest.NewRequest(method, "/", strings.NewReader(tt.query))
+					r.Header.Set(contentTypeHeader, "application/x-www-form-urlencoded")
+				}
+
+				got := new(testpb.Message)
+				err := NewContext(httptest.NewRecorder(), r).Bind(got)
+				if tt.wantErr {
+					if err == nil {
+						t.Fatalf("%s: expect error, got %v", method, got)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s: %v", method, err)
+				}
+				if !proto.Equal(got, tt.want) {
+					t.Fatalf("%s: got %v, want %v", method, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package httpx
+
+import "net/http"
+
+// CallOption configures a Client.Invoke call
+type CallOption interface {
+	// before is called before the call is sent
+	before(*callInfo) error
+
+	// after is called after the call has completed
+	after(*callInfo)
+}
+
+type callInfo struct {
+	contentType string
+	operation   string
+	template    string
+	header      http.Header
+}
+
+type callOption struct {
+	beforeFn func(*callInfo) error
+	afterFn  func(*callInfo)
+}
+
+func (o callOption) before(info *callInfo) error {
+	if o.beforeFn == nil {
+		return nil
+	}
+	return o.beforeFn(info)
+}
+
+func (o callOption) after(info *callInfo) {
+	if o.afterFn != nil {
+		o.afterFn(info)
+	}
+}
+
+// ContentType specify the request body Codec type of the call
+func ContentType(typename string) CallOption {
+	return callOption{beforeFn: func(info *callInfo) error {
+		info.contentType = typename
+		return nil
+	}}
+}
+
+// Header retrieves the response header of the call
+func Header(header *http.Header) CallOption {
+	return callOption{afterFn: func(info *callInfo) {
+		*header = info.header
+	}}
+}
+
+// Operation specify the middleware.Context Operation of the call,
+// eg: /pb.User/Login, it's the path template if absent
+func Operation(operation string) CallOption {
+	return callOption{beforeFn: func(info *callInfo) error {
+		info.operation = operation
+		return nil
+	}}
+}
+
+// PathTemplate specify the middleware.Context Template of the call,
+// eg: /user/{id}, it's the request path if absent
+func PathTemplate(template string) CallOption {
+	return callOption{beforeFn: func(info *callInfo) error {
+		info.template = template
+		return nil
+	}}
+}
@@ -0,0 +1,294 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charliego3/pallas/encoding"
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/registry"
+	"github.com/charliego3/pallas/utility"
+)
+
+const discoveryScheme = "discovery"
+
+// ErrNoEndpoint is returned when discovery has no available instance
+var ErrNoEndpoint = errors.New("[HTTP] no endpoint available")
+
+// DecodeErrorFunc decode the response to error, returns nil if it's succeed
+type DecodeErrorFunc func(ctx context.Context, res *http.Response) error
+
+// DefaultErrorDecoder returns errorx.Error when the status code is not 2xx
+func DefaultErrorDecoder(_ context.Context, res *http.Response) error {
+	if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	e := errorx.New(errorx.CodeFromHTTPStatus(res.StatusCode), errorx.UnknownReason, http.StatusText(res.StatusCode))
+	e.Status = res.StatusCode
+	data, err := io.ReadAll(res.Body)
+	if err != nil || len(data) == 0 {
+		return e
+	}
+
+	codec, ok := encoding.LookupCodec(SubContentType(res.Header.Get(contentTypeHeader)))
+	if !ok || codec.Unmarshal(data, e) != nil || utility.IsBlank(e.Message) {
+		e.Message = strings.TrimSpace(string(data))
+	}
+	return e
+}
+
+// Client is a http client which runs the middleware chain on outgoing calls
+type Client struct {
+	*clientOptions
+	client *http.Client
+	target *url.URL
+
+	// watcher and endpoints are used when target is discovery:///service-name
+	watcher   registry.Watcher
+	mu        sync.RWMutex
+	endpoints []string
+	next      atomic.Uint32
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// Dial creates a client to the target, the target is base url like http://127.0.0.1:8000,
+// or discovery:///service-name when WithDiscovery is specified
+func Dial(ctx context.Context, target string, opts ...utility.Option[Client]) (*Client, error) {
+	c := &Client{closed: make(chan struct{})}
+	c.clientOptions = &clientOptions{
+		transport:  http.DefaultTransport,
+		errDecoder: DefaultErrorDecoder,
+		logger:     slog.Default(),
+	}
+	utility.Apply(c, opts...)
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	c.target = u
+
+	if c.tlsConfig != nil {
+		if transport, ok := c.transport.(*http.Transport); ok {
+			transport = transport.Clone()
+			transport.TLSClientConfig = c.tlsConfig
+			c.transport = transport
+		}
+	}
+	c.client = &http.Client{Transport: c.transport}
+
+	if u.Scheme == discoveryScheme {
+		if c.discovery == nil {
+			return nil, fmt.Errorf("[HTTP] target %q requires WithDiscovery", target)
+		}
+		if err = c.watch(ctx, strings.TrimPrefix(u.Path, "/")); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// NewClient is Dial with background context
+func NewClient(target string, opts ...utility.Option[Client]) (*Client, error) {
+	return Dial(context.Background(), target, opts...)
+}
+
+// Invoke send the request to path, args is encoded as request body if not nil,
+// the response body is decoded into reply
+func (c *Client) Invoke(ctx context.Context, method, path string, args, reply any, opts ...CallOption) error {
+	info := &callInfo{contentType: defaultCodecType}
+	for _, opt := range opts {
+		if err := opt.before(info); err != nil {
+			return err
+		}
+	}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	hctx := middleware.NewHTTPClientContext(ctx, method, path, args)
+	if info.template != "" {
+		hctx.Template = info.template
+	}
+	// the ad-hoc call without template and operation keeps
+	// the method as Operation, it's the label of metrics
+	hctx.Operation = utility.DString(info.operation, utility.DString(info.template, hctx.Operation))
+	m := middleware.Chain(c.middlewares...)
+	_, err := m(func(mctx *middleware.Context) (any, error) {
+		req, err := c.newRequest(mctx, info, method, path, args)
+		if err != nil {
+			return nil, err
+		}
+		for k, vals := range mctx.ReqHeader {
+			for _, v := range vals {
+				req.Header.Add(k, v)
+			}
+		}
+
+		res, err := c.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+
+		info.header = res.Header
+		for k, v := range res.Header {
+			mctx.ResHeader.Add(k, v...)
+		}
+		if err = c.errDecoder(mctx, res); err != nil {
+			return nil, err
+		}
+		return reply, decodeReply(res, reply)
+	})(hctx)
+
+	for _, opt := range opts {
+		opt.after(info)
+	}
+	return err
+}
+
+func (c *Client) newRequest(ctx context.Context, info *callInfo, method, path string, args any) (*http.Request, error) {
+	base, err := c.endpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if args != nil {
+		data, err := encoding.CodecWithType(info.contentType).Marshal(args)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, base+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if args != nil {
+		req.Header.Set(contentTypeHeader, "application/"+info.contentType)
+	}
+	return req, nil
+}
+
+// decodeReply decode the response body with the response Content-Type
+func decodeReply(res *http.Response, reply any) error {
+	if reply == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+
+	contentType := SubContentType(res.Header.Get(contentTypeHeader))
+	codec, ok := encoding.LookupCodec(contentType)
+	if !ok {
+		return fmt.Errorf("[HTTP] unsupported response Content-Type: %s", contentType)
+	}
+	return codec.Unmarshal(data, reply)
+}
+
+// endpoint returns the base url of the next request
+func (c *Client) endpoint() (string, error) {
+	if c.watcher == nil {
+		return strings.TrimSuffix(c.target.String(), "/"), nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.endpoints) == 0 {
+		return "", ErrNoEndpoint
+	}
+	n := c.next.Add(1)
+	return c.endpoints[int(n)%len(c.endpoints)], nil
+}
+
+// watch the service instances and round-robin between them,
+// the watcher outlives the ctx until Client closed
+func (c *Client) watch(ctx context.Context, name string) error {
+	w, err := c.discovery.Watch(context.WithoutCancel(ctx), name)
+	if err != nil {
+		return err
+	}
+
+	instances, err := w.Next()
+	if err != nil {
+		_ = w.Stop()
+		return err
+	}
+	c.update(instances)
+	c.watcher = w
+
+	go func() {
+		defer w.Stop()
+		for {
+			instances, err := w.Next()
+			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					return
+				}
+				c.logger.Error("[HTTP] watch failed", slog.String("service", name), slog.Any("err", err))
+
+				// retry later unless the Client is closed
+				timer := time.NewTimer(time.Second)
+				select {
+				case <-c.closed:
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+				continue
+			}
+			c.update(instances)
+		}
+	}()
+	return nil
+}
+
+func (c *Client) update(instances []*registry.ServiceInstance) {
+	scheme := "http"
+	if c.tlsConfig != nil {
+		scheme = "https"
+	}
+
+	endpoints := make([]string, 0, len(instances))
+	for _, ins := range instances {
+		if host, ok := ins.Endpoint("http"); ok {
+			endpoints = append(endpoints, scheme+"://"+host)
+		}
+	}
+
+	c.mu.Lock()
+	c.endpoints = endpoints
+	c.mu.Unlock()
+}
+
+// Close stop watching the discovery
+func (c *Client) Close() (err error) {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		if c.watcher != nil {
+			err = c.watcher.Stop()
+		}
+	})
+	return
+}
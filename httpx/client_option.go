@@ -0,0 +1,72 @@
+package httpx
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/registry"
+	"github.com/charliego3/pallas/utility"
+)
+
+type clientOptions struct {
+	transport   http.RoundTripper
+	tlsConfig   *tls.Config
+	middlewares []middleware.Middleware
+	timeout     time.Duration
+	discovery   registry.Discovery
+	errDecoder  DecodeErrorFunc
+	logger      *slog.Logger
+}
+
+// WithClientMiddleware inject Middleware to the outgoing calls
+func WithClientMiddleware(middlewares ...middleware.Middleware) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	})
+}
+
+// WithClientTimeout specify the default timeout of calls,
+// the call's own deadline takes precedence if it's earlier
+func WithClientTimeout(timeout time.Duration) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.timeout = timeout
+	})
+}
+
+// WithClientTLS specify the TLS configuration used by the transport,
+// the discovered endpoints are requested with https
+func WithClientTLS(config *tls.Config) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.tlsConfig = config
+	})
+}
+
+// WithTransport specify the http.RoundTripper, default is http.DefaultTransport
+func WithTransport(transport http.RoundTripper) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.transport = transport
+	})
+}
+
+// WithDiscovery resolve discovery:///service-name target from the discovery
+func WithDiscovery(d registry.Discovery) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.discovery = d
+	})
+}
+
+// WithErrorDecoder specify how to decode the non 2xx response to error
+func WithErrorDecoder(decoder DecodeErrorFunc) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.errDecoder = decoder
+	})
+}
+
+func WithClientLogger(logger *slog.Logger) utility.Option[Client] {
+	return utility.OptionFunc[Client](func(c *Client) {
+		c.logger = logger
+	})
+}
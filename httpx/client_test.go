@@ -0,0 +1,280 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/registry"
+	"github.com/charliego3/pallas/registry/memory"
+	"github.com/charliego3/pallas/testdata/testpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	client, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestClientInvoke(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Server", r.Header.Get("X-Client"))
+		w.Header().Set(contentTypeHeader, "application/json")
+		var in testpb.Nested
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		_, _ = w.Write([]byte(`{"value":"` + r.URL.Path + ":" + in.Value + `"}`))
+	}))
+	defer srv.Close()
+
+	var seen *middleware.Context
+	client, err := NewClient(srv.URL, WithClientMiddleware(func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			seen = ctx
+			ctx.ReqHeader.Set("X-Client", "pallas")
+			return next(ctx)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		before, after bool
+		header        http.Header
+		reply         testpb.Nested
+	)
+	hooks := callOption{
+		beforeFn: func(*callInfo) error { before = true; return nil },
+		afterFn:  func(*callInfo) { after = true },
+	}
+	err = client.Invoke(context.Background(), http.MethodPost, "/nested/1", &testpb.Nested{Value: "in"}, &reply,
+		hooks, Header(&header), Operation("/pallas.testpb.Service/Get"), PathTemplate("/nested/{id}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Value != "/nested/1:in" || !before || !after {
+		t.Fatalf("reply = %v, before = %v, after = %v", reply.Value, before, after)
+	}
+	if header.Get("X-Server") != "pallas" || seen.ResHeader.Get("X-Server") != "pallas" {
+		t.Fatalf("middleware header is not sent: %v", header)
+	}
+	if seen.Kind != middleware.KindHTTPClient || seen.Operation != "/pallas.testpb.Service/Get" || seen.Template != "/nested/{id}" {
+		t.Fatalf("context = %s %s %s", seen.Kind, seen.Operation, seen.Template)
+	}
+
+	// the ad-hoc call is not labeled by the concrete path
+	if err = client.Invoke(context.Background(), http.MethodGet, "/nested/2", nil, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if seen.Operation != http.MethodGet || seen.Template != "/nested/2" {
+		t.Fatalf("ad-hoc context = %s %s", seen.Operation, seen.Template)
+	}
+
+	// the before hook error aborts the call
+	abort := errors.New("abort")
+	err = client.Invoke(context.Background(), http.MethodGet, "/", nil, nil, callOption{
+		beforeFn: func(*callInfo) error { return abort },
+	})
+	if !errors.Is(err, abort) {
+		t.Fatalf("before error = %v", err)
+	}
+}
+
+func TestClientErrorDecoder(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		check   func(error) bool
+	}{
+		{
+			name: "errorx",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				e := errorx.NotFound("USER_NOT_FOUND", "user not found").WithMetadata(map[string]string{"id": "1"})
+				w.Header().Set(contentTypeHeader, "application/json")
+				w.WriteHeader(e.HTTPStatus())
+				_ = json.NewEncoder(w).Encode(e)
+			},
+			check: func(err error) bool {
+				e := errorx.FromError(err)
+				return errorx.IsNotFound(err) && e.Reason == "USER_NOT_FOUND" &&
+					e.Message == "user not found" && e.Metadata["id"] == "1"
+			},
+		},
+		{
+			name: "plain text",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				http.Error(w, "upstream is down", http.StatusBadGateway)
+			},
+			check: func(err error) bool {
+				e := errorx.FromError(err)
+				return e.HTTPStatus() == http.StatusBadGateway && e.Message == "upstream is down"
+			},
+		},
+		{
+			name: "empty body",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			check: func(err error) bool {
+				return errorx.IsPermissionDenied(err) && errorx.FromError(err).Message == "Forbidden"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.handler)
+			err := client.Invoke(context.Background(), http.MethodGet, "/", nil, new(testpb.Nested))
+			if !tt.check(err) {
+				t.Fatalf("err = %v", err)
+			}
+		})
+	}
+}
+
+func TestClientNoEndpoint(t *testing.T) {
+	client, err := NewClient("discovery:///user", WithDiscovery(memory.New()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	err = client.Invoke(context.Background(), http.MethodGet, "/", nil, nil)
+	if !errors.Is(err, ErrNoEndpoint) {
+		t.Fatalf("err = %v, want ErrNoEndpoint", err)
+	}
+
+	if _, err = NewClient("discovery:///user"); err == nil {
+		t.Fatal("expect error without WithDiscovery")
+	}
+}
+
+// failingWatcher returns an error for each Next until stopped
+type failingWatcher struct {
+	nexts   atomic.Int32
+	stopped chan struct{}
+	once    sync.Once
+}
+
+func (w *failingWatcher) Next() ([]*registry.ServiceInstance, error) {
+	if w.nexts.Add(1) == 1 {
+		return nil, nil
+	}
+	select {
+	case <-w.stopped:
+		return nil, context.Canceled
+	default:
+		return nil, errors.New("unavailable")
+	}
+}
+
+func (w *failingWatcher) Stop() error {
+	w.once.Do(func() { close(w.stopped) })
+	return nil
+}
+
+type watcherDiscovery struct{ w registry.Watcher }
+
+func (d watcherDiscovery) GetService(context.Context, string) ([]*registry.ServiceInstance, error) {
+	return nil, nil
+}
+
+func (d watcherDiscovery) Watch(context.Context, string) (registry.Watcher, error) {
+	return d.w, nil
+}
+
+func TestClientWatchStop(t *testing.T) {
+	// the watcher is stopped if the first Next fails
+	w := &failingWatcher{stopped: make(chan struct{})}
+	w.nexts.Store(1)
+	if _, err := NewClient("discovery:///user", WithDiscovery(watcherDiscovery{w})); err == nil {
+		t.Fatal("expect error of the first Next")
+	}
+	select {
+	case <-w.stopped:
+	default:
+		t.Fatal("watcher is not stopped after the first Next failed")
+	}
+
+	// the retrying is interrupted by Close
+	w = &failingWatcher{stopped: make(chan struct{})}
+	client, err := NewClient("discovery:///user", WithDiscovery(watcherDiscovery{w}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for w.nexts.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if err = client.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Close(); err != nil {
+		t.Fatalf("close twice: %v", err)
+	}
+	nexts := w.nexts.Load()
+	time.Sleep(1500 * time.Millisecond)
+	if n := w.nexts.Load(); n != nexts {
+		t.Fatalf("watch is retried %d times after closed", n-nexts)
+	}
+}
+
+func TestEncodeURL(t *testing.T) {
+	msg := &testpb.Message{
+		Name:     "a b",
+		Id:       1,
+		Kind:     testpb.Kind_KIND_ADMIN,
+		Tags:     []string{"x", "y"},
+		Labels:   map[string]string{"env": "prod"},
+		Quotas:   map[string]int64{"cpu": 2},
+		Nickname: wrapperspb.String("nick"),
+		Nested:   &testpb.Nested{Value: "v", Kinds: []testpb.Kind{testpb.Kind_KIND_USER}},
+		Contact:  &testpb.Message_Email{Email: "a@b.c"},
+		Data:     []byte{0xff},
+	}
+	tests := []struct {
+		template, body string
+		want           string
+	}{
+		{"/messages/{id}", "", "/messages/1?" + url.Values{
+			"name": {"a b"}, "kind": {"KIND_ADMIN"}, "tags": {"x", "y"},
+			"labels[env]": {"prod"}, "quotas[cpu]": {"2"}, "nickname": {"nick"},
+			"nested.value": {"v"}, "nested.kinds": {"KIND_USER"}, "email": {"a@b.c"}, "data": {"_w=="},
+		}.Encode()},
+		{"/messages/{nested.value}/{name}", "*", "/messages/v/a%20b"},
+		{"/messages/{id}", "nested", "/messages/1?" + url.Values{
+			"name": {"a b"}, "kind": {"KIND_ADMIN"}, "tags": {"x", "y"},
+			"labels[env]": {"prod"}, "quotas[cpu]": {"2"}, "nickname": {"nick"},
+			"email": {"a@b.c"}, "data": {"_w=="},
+		}.Encode()},
+	}
+	for _, tt := range tests {
+		if got := EncodeURL(tt.template, msg, tt.body); got != tt.want {
+			t.Errorf("EncodeURL(%s, %q)\n got %s\nwant %s", tt.template, tt.body, got, tt.want)
+		}
+	}
+
+	// the encoded query is bound back to the same message
+	u, _ := url.Parse(tests[0].want)
+	got := &testpb.Message{Id: 1}
+	if err := bindValues(got, u.Query(), false); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, msg) {
+		t.Fatalf("bound = %v, want %v", got, msg)
+	}
+}
@@ -2,7 +2,7 @@ package httpx
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"github.com/charliego3/pallas/utility"
 	"io"
 	"net/http"
@@ -79,15 +79,19 @@ func (c *Context) BindXML(v any) error {
 	return c.bind(xml.Type, v)
 }
 
+// BindQuery binds the query parameters, the keys of proto.Message
+// are matched by proto name or json name, eg: user_id, userId or user.name
 func (c *Context) BindQuery(v any) error {
 	values := c.URL.Query()
 	if len(values) == 0 {
 		return nil
 	}
 
-	return valuesDecoder.Decode(v, values)
+	return bindValues(v, values, true)
 }
 
+// BindVars binds the route variables, the nested field path like
+// book.id is supported when v is proto.Message
 func (c *Context) BindVars(v any) error {
 	vars := mux.Vars(c.Request)
 	if len(vars) == 0 {
@@ -96,9 +100,13 @@ func (c *Context) BindVars(v any) error {
 
 	values := make(url.Values)
 	for k, v := range vars {
+		if strings.HasPrefix(k, anonymousVarPrefix) {
+			continue
+		}
 		values.Set(k, v)
 	}
-	return valuesDecoder.Decode(v, values)
+
+	return bindValues(v, values, false)
 }
 
 func (c *Context) BindForm(v any) error {
@@ -109,7 +117,7 @@ func (c *Context) BindForm(v any) error {
 		return nil
 	}
 
-	return valuesDecoder.Decode(v, c.PostForm)
+	return bindValues(v, c.PostForm, true)
 }
 
 func (c *Context) BindMultipartForm(v any) error {
@@ -119,7 +127,7 @@ func (c *Context) BindMultipartForm(v any) error {
 
 	var err error
 	if len(c.MultipartForm.Value) > 0 {
-		if err = valuesDecoder.Decode(v, c.MultipartForm.Value); err != nil {
+		if err = bindValues(v, c.MultipartForm.Value, true); err != nil {
 			return err
 		}
 	}
@@ -128,15 +136,9 @@ func (c *Context) BindMultipartForm(v any) error {
 	return nil
 }
 
-func (c *Context) Bind(v any) error {
-	if err := c.BindVars(v); err != nil {
-		return err
-	}
-
-	if c.Method == http.MethodGet {
-		return c.BindQuery(v)
-	}
-
+// BindBody binds the request body by Content-Type,
+// an empty body leaves v untouched
+func (c *Context) BindBody(v any) error {
 	contentType := c.Header.Get(contentTypeHeader)
 	contentType = SubContentType(contentType)
 	switch contentType {
@@ -145,10 +147,25 @@ func (c *Context) Bind(v any) error {
 	case "form-data":
 		return c.BindMultipartForm(v)
 	default:
-		return c.bind(contentType, v)
+		err := c.bind(contentType, v)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
 	}
 }
 
+func (c *Context) Bind(v any) error {
+	if err := c.BindVars(v); err != nil {
+		return err
+	}
+
+	if c.Method == http.MethodGet {
+		return c.BindQuery(v)
+	}
+	return c.BindBody(v)
+}
+
 func (c *Context) write(contentType string, v any, code []int) error {
 	codec := encoding.CodecWithType(contentType)
 	c.Writer.Header().Set("Content-Type", "application/"+codec.Type())
@@ -161,13 +178,23 @@ func (c *Context) write(contentType string, v any, code []int) error {
 	if err != nil {
 		return err
 	}
-	_, err = fmt.Fprint(c.Writer, b)
+	_, err = c.Writer.Write(b)
 	return err
 }
 
 func (c *Context) Write(v any, code ...int) error {
-	contentType := SubContentType(c.Header.Get("Accept"))
-	return c.write(contentType, v, code)
+	return c.write(c.accept().Type(), v, code)
+}
+
+// accept returns the first registered Codec of Accept header,
+// the default Codec is used if none of them is registered
+func (c *Context) accept() encoding.Codec {
+	for _, accept := range strings.Split(c.Header.Get("Accept"), ",") {
+		if codec, ok := encoding.LookupCodec(SubContentType(strings.TrimSpace(accept))); ok {
+			return codec
+		}
+	}
+	return encoding.CodecWithType(defaultCodecType)
 }
 
 func (c *Context) JSON(v any, code ...int) error {
@@ -0,0 +1,131 @@
+package httpx
+
+import (
+	"encoding/base64"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// pathVarRegexp matches {field.path} and {field.path=pattern}
+var pathVarRegexp = regexp.MustCompile(`{([\w.]+)(?:=([^{}]*))?}`)
+
+// EncodeURL fill the path variables of the google.api.http template with msg fields,
+// the fields are neither in the path nor the body will be encoded as query,
+// body is the HttpRule body: "*" means the whole message is the request body
+func EncodeURL(template string, msg proto.Message, body string) string {
+	if msg == nil {
+		return template
+	}
+
+	m := msg.ProtoReflect()
+	pathFields := make(map[string]struct{})
+	path := pathVarRegexp.ReplaceAllStringFunc(template, func(v string) string {
+		matches := pathVarRegexp.FindStringSubmatch(v)
+		pathFields[matches[1]] = struct{}{}
+		value := pathValue(m, matches[1])
+		if hasMultiSegments(matches[2]) {
+			segments := strings.Split(value, "/")
+			for i, s := range segments {
+				segments[i] = url.PathEscape(s)
+			}
+			return strings.Join(segments, "/")
+		}
+		return url.PathEscape(value)
+	})
+
+	if body == "*" {
+		return path
+	}
+
+	query := make(url.Values)
+	encodeQuery(m, "", "", pathFields, body, query)
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+// hasMultiSegments reports whether the variable pattern contains more than one segment
+func hasMultiSegments(pattern string) bool {
+	return strings.Contains(pattern, "/") || strings.Contains(pattern, "**")
+}
+
+// pathValue returns the string value of the dotted proto field path
+func pathValue(m protoreflect.Message, fieldPath string) string {
+	names := strings.Split(fieldPath, ".")
+	for i, name := range names {
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return ""
+		}
+		if i == len(names)-1 {
+			return formatValue(fd, m.Get(fd))
+		}
+		if fd.Kind() != protoreflect.MessageKind || !m.Has(fd) {
+			return ""
+		}
+		m = m.Get(fd).Message()
+	}
+	return ""
+}
+
+// encodeQuery add the populated fields to query keyed by json name,
+// the nested message fields are keyed by dotted path eg: user.name,
+// the map entries are keyed by the map key eg: labels[env]
+func encodeQuery(m protoreflect.Message, protoPrefix, jsonPrefix string, excludes map[string]struct{}, body string, query url.Values) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		protoPath := protoPrefix + string(fd.Name())
+		if _, ok := excludes[protoPath]; ok || protoPath == body {
+			return true
+		}
+
+		key := jsonPrefix + fd.JSONName()
+		switch {
+		case fd.IsMap():
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				query.Add(key+"["+k.String()+"]", formatValue(fd.MapValue(), mv))
+				return true
+			})
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				query.Add(key, formatValue(fd, list.Get(i)))
+			}
+		case fd.Kind() == protoreflect.MessageKind && !isWellKnown(fd.Message()):
+			encodeQuery(v.Message(), protoPath+".", key+".", excludes, body, query)
+		default:
+			query.Add(key, formatValue(fd, v))
+		}
+		return true
+	})
+}
+
+func isWellKnown(md protoreflect.MessageDescriptor) bool {
+	return md.ParentFile().Package() == "google.protobuf"
+}
+
+// formatValue returns the string value of a singular field value
+func formatValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return v.String()
+	case protoreflect.BytesKind:
+		return base64.URLEncoding.EncodeToString(v.Bytes())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		data, err := protojson.Marshal(v.Message().Interface())
+		if err != nil {
+			return ""
+		}
+		return strings.Trim(string(data), `"`)
+	default:
+		return v.String()
+	}
+}
@@ -0,0 +1,52 @@
+package httpx
+
+import (
+	stdjson "encoding/json"
+
+	"github.com/charliego3/pallas/encoding/json"
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/utility"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrorEncoder writes the error returned from Handler to response
+type ErrorEncoder func(*Context, error)
+
+// ResponseEncoder writes the reply returned from Handler to response
+type ResponseEncoder func(*Context, any) error
+
+// DefaultErrorEncoder writes errorx.Error with the mapped http status,
+// the content type is negotiated by Accept header
+func DefaultErrorEncoder(c *Context, err error) {
+	e := errorx.FromError(err)
+	_ = c.Write(e, e.HTTPStatus())
+}
+
+// DefaultResponseEncoder writes the reply with 200 status,
+// the content type is negotiated by Accept header
+func DefaultResponseEncoder(c *Context, v any) error {
+	return c.Write(v)
+}
+
+// EntityErrorEncoder writes the error as utility.ResponseEntity
+// {"code":404,"message":"...","reason":"...","metadata":{}}
+// with the mapped http status
+func EntityErrorEncoder(c *Context, err error) {
+	e := errorx.FromError(err)
+	_ = c.Write(utility.NewReasonResponse(e.HTTPStatus(), e.Reason, e.Message, e.Metadata), e.HTTPStatus())
+}
+
+// EntityResponseEncoder writes the reply as the payload
+// of utility.ResponseEntity {"code":200,"message":"OK","payload":{}}
+func EntityResponseEncoder(c *Context, v any) error {
+	// the payload is not the top level value which is encoded by
+	// the codec, so proto.Message is encoded with protojson here
+	if m, ok := v.(proto.Message); ok && c.accept().Type() == json.Type {
+		data, err := json.MarshalOptions.Marshal(m)
+		if err != nil {
+			return err
+		}
+		v = stdjson.RawMessage(data)
+	}
+	return c.Write(utility.NewResponse(v))
+}
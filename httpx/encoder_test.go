@@ -0,0 +1,65 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charliego3/pallas/errorx"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestResponseEntity(t *testing.T) {
+	r := NewRouter()
+	r.ene = EntityErrorEncoder
+	r.rse = EntityResponseEncoder
+	r.GET("/ok", func(*Context) (any, error) {
+		return wrapperspb.String("pallas"), nil
+	})
+	r.GET("/missing", func(*Context) (any, error) {
+		return nil, errorx.NotFound("BOOK_NOT_FOUND", "book not found").
+			WithMetadata(map[string]string{"id": "1"})
+	})
+	r.GET("/boom", func(*Context) (any, error) {
+		return nil, errors.New("boom")
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/ok", http.StatusOK, `{"code":200,"message":"OK","payload":"pallas"}`},
+		{"/missing", http.StatusNotFound, `{"code":404,"message":"book not found","reason":"BOOK_NOT_FOUND","metadata":{"id":"1"}}`},
+		{"/boom", http.StatusInternalServerError, `{"code":500,"message":"unknown error"}`},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Accept", "*/*")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status || strings.TrimSpace(w.Body.String()) != tt.body {
+			t.Errorf("GET %s = %d %s, want %d %s", tt.path, w.Code, w.Body, tt.status, tt.body)
+		}
+	}
+}
+
+func TestDefaultErrorEncoder(t *testing.T) {
+	r := NewRouter()
+	r.GET("/err", func(*Context) (any, error) {
+		return nil, errors.New("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/err", nil)
+	req.Header.Set("Accept", "text/html, application/xml;q=0.9")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/xml" {
+		t.Fatalf("content type = %s, want application/xml", ct)
+	}
+}
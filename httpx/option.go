@@ -10,14 +10,10 @@ import (
 	"time"
 
 	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/middleware/recovery"
 	"github.com/charliego3/pallas/utility"
 )
 
-type CallOption interface {
-	before()
-	after()
-}
-
 // WithAddr optionally specifies the TCP address for the server to listen on
 func WithAddr(network, addr string) utility.Option[Server] {
 	return utility.OptionFunc[Server](func(s *Server) {
@@ -189,6 +185,39 @@ func WithLogger(logger *slog.Logger) utility.Option[Server] {
 	})
 }
 
+// WithErrorEncoder specifies the encoder of the error returned from Handler,
+// DefaultErrorEncoder is used by default
+func WithErrorEncoder(encoder ErrorEncoder) utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.ene = encoder
+	})
+}
+
+// WithResponseEncoder specifies the encoder of the reply returned from Handler,
+// DefaultResponseEncoder is used by default
+func WithResponseEncoder(encoder ResponseEncoder) utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.rse = encoder
+	})
+}
+
+// WithResponseEntity wraps the reply and error with utility.ResponseEntity
+// envelope: {"code":200,"message":"OK","payload":{}}
+func WithResponseEntity() utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.ene = EntityErrorEncoder
+		s.rse = EntityResponseEncoder
+	})
+}
+
+// WithRecovery customize the recovery of panic, it's always the
+// outermost middleware, the panic converts to errorx Internal by default
+func WithRecovery(opts ...utility.Option[recovery.Recoverer]) utility.Option[Server] {
+	return utility.OptionFunc[Server](func(s *Server) {
+		s.recoverer = recovery.New(opts...)
+	})
+}
+
 func WithMultipartMaxSize(size int64) utility.Option[Server] {
 	return utility.OptionFunc[Server](func(s *Server) {
 		s.maxMultipartSize = size
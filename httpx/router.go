@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 
 	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/middleware/recovery"
 	"github.com/charliego3/pallas/utility"
 	"github.com/gorilla/mux"
 )
@@ -15,14 +16,6 @@ var _ http.Handler = (*Router)(nil)
 
 type Handler func(*Context) (any, error)
 
-type ErrorEncoder func(*Context, error)
-
-func defaultErrEncoder(c *Context, err error) {
-	c.Write(map[string]any{
-		"err": err.Error(),
-	}, http.StatusInternalServerError)
-}
-
 type RouteWalkFunc func(method, path string)
 
 type Router struct {
@@ -35,6 +28,12 @@ type Router struct {
 	// ene route error processor
 	ene ErrorEncoder
 
+	// rse route reply processor
+	rse ResponseEncoder
+
+	// recoverer recovers the panic of handler and middlewares
+	recoverer *recovery.Recoverer
+
 	maxMultipartSize int64
 }
 
@@ -43,15 +42,18 @@ func NewRouter(middlewares ...middleware.Middleware) *Router {
 	r.maxMultipartSize = 32 << 20
 	r.Router = mux.NewRouter()
 	r.middlewares = middlewares
-	r.ene = defaultErrEncoder
+	r.ene = DefaultErrorEncoder
+	r.rse = DefaultResponseEncoder
+	r.recoverer = recovery.New()
 	return r
 }
 
 func (r *Router) Walk(fn RouteWalkFunc) error {
 	return r.Router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		// the route without methods matches any method
 		methods, err := route.GetMethods()
 		if err != nil {
-			return err
+			methods = []string{"*"}
 		}
 		template, err := route.GetPathTemplate()
 		if err != nil {
@@ -64,13 +66,24 @@ func (r *Router) Walk(fn RouteWalkFunc) error {
 	})
 }
 
-func (r *Router) handle(method, path string, handler Handler, middlewares ...middleware.Middleware) {
+// handle register the handler with mux path, the template and operation
+// are populated to middleware.Context, operation is the template if empty
+func (r *Router) handle(method, path, template, operation string, handler Handler, middlewares ...middleware.Middleware) {
+	template = filepath.Join(r.prefix, template)
+	if operation == "" {
+		operation = template
+	}
+
 	m := middleware.Chain(r.middlewares...)
 	m = m.Append(middlewares...)
+	m = middleware.Chain(r.recoverer.Middleware(), m)
 	next := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := NewContext(w, req)
 		mctx := middleware.NewHTTPContext(req)
+		mctx.Operation = operation
+		mctx.Template = template
 		reply, err := m(func(c *middleware.Context) (any, error) {
+			ctx.Context = c.Context
 			reply, err := handler(ctx)
 			c.Payload = ctx.Payload
 			return reply, err
@@ -87,7 +100,7 @@ func (r *Router) handle(method, path string, handler Handler, middlewares ...mid
 			return
 		}
 
-		if err = ctx.Write(reply); err != nil {
+		if err = r.rse(ctx, reply); err != nil {
 			r.ene(ctx, err)
 		}
 	}))
@@ -97,58 +110,85 @@ func (r *Router) handle(method, path string, handler Handler, middlewares ...mid
 	}
 }
 
+// HandleTemplate register the handler with google.api.http path template,
+// eg: /v1/{name=shelves/*/books/*}, the variables are bound by Context.BindVars
+func (r *Router) HandleTemplate(method, template string, handler Handler, middlewares ...middleware.Middleware) {
+	r.HandleOperation("", method, template, handler, middlewares...)
+}
+
+// HandleOperation register the handler like HandleTemplate, the operation
+// is the full grpc method of the binding, eg: /pb.User/Login, so the
+// middlewares key the http and grpc calls of the same rpc by Context.Operation,
+// it's the template if empty
+func (r *Router) HandleOperation(operation, method, template string, handler Handler, middlewares ...middleware.Middleware) {
+	t, err := CompileTemplate(template)
+	if err != nil {
+		panic(err)
+	}
+	r.handle(method, t.Route, t.Raw, operation, handler, middlewares...)
+}
+
+// Mount register the http.Handler without middlewares, eg: health probes
+func (r *Router) Mount(path string, handler http.Handler) {
+	r.Router.Handle(filepath.Join(r.prefix, path), handler)
+}
+
 func (r *Router) Handle(path string, handler Handler, middlewares ...middleware.Middleware) {
-	r.handle("", path, handler, middlewares...)
+	r.handle("", path, path, "", handler, middlewares...)
 }
 
 func (r *Router) HandleFunc(path string, handler Handler, middleware ...middleware.Middleware) {
-	r.handle("", path, handler, middleware...)
+	r.handle("", path, path, "", handler, middleware...)
 }
 
 func (r *Router) HandleMethod(method, path string, handler Handler, middlewares ...middleware.Middleware) {
-	r.handle(method, path, handler, middlewares...)
+	r.handle(method, path, path, "", handler, middlewares...)
 }
 
 func (r *Router) GET(path string, handler Handler, middlewares ...middleware.Middleware) {
-	r.handle(http.MethodGet, path, handler, middlewares...)
+	r.handle(http.MethodGet, path, path, "", handler, middlewares...)
 }
 
 func (r *Router) POST(path string, handler Handler, middlewares ...middleware.Middleware) {
-	r.handle(http.MethodPost, path, handler, middlewares...)
+	r.handle(http.MethodPost, path, path, "", handler, middlewares...)
 }
 
 func (r *Router) PUT(path string, handler Handler, middlewares ...middleware.Middleware) {
-	r.handle(http.MethodPut, path, handler, middlewares...)
+	r.handle(http.MethodPut, path, path, "", handler, middlewares...)
 }
 
 func (r *Router) DELETE(path string, handler Handler, middlewares ...middleware.Middleware) {
-	r.handle(http.MethodDelete, path, handler, middlewares...)
+	r.handle(http.MethodDelete, path, path, "", handler, middlewares...)
 }
 
 func (r *Router) HEAD(path string, handler Handler, middlewares ...middleware.Middleware) {
-	r.handle(http.MethodHead, path, handler, middlewares...)
+	r.handle(http.MethodHead, path, path, "", handler, middlewares...)
 }
 
 func (r *Router) PATCH(path string, handler Handler, middlewares ...middleware.Middleware) {
-	r.handle(http.MethodPatch, path, handler, middlewares...)
+	r.handle(http.MethodPatch, path, path, "", handler, middlewares...)
 }
 
 func (r *Router) CONNECT(path string, handler Handler, middlewares ...middleware.Middleware) {
-	r.handle(http.MethodConnect, path, handler, middlewares...)
+	r.handle(http.MethodConnect, path, path, "", handler, middlewares...)
 }
 
 func (r *Router) OPTIONS(path string, handler Handler, middlewares ...middleware.Middleware) {
-	r.handle(http.MethodOptions, path, handler, middlewares...)
+	r.handle(http.MethodOptions, path, path, "", handler, middlewares...)
 }
 
 func (r *Router) TRACE(path string, handler Handler, middlewares ...middleware.Middleware) {
-	r.handle(http.MethodTrace, path, handler, middlewares...)
+	r.handle(http.MethodTrace, path, path, "", handler, middlewares...)
 }
 
 func (r *Router) Group(prefix string, middlewares ...middleware.Middleware) *Router {
 	route := new(Router)
 	route.prefix = filepath.Join(r.prefix, prefix)
 	route.Router = r.Router
+	route.ene = r.ene
+	route.rse = r.rse
+	route.recoverer = r.recoverer
+	route.maxMultipartSize = r.maxMultipartSize
 	route.middlewares = append(route.middlewares, append(r.middlewares, middlewares...)...)
 	return route
 }
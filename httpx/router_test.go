@@ -0,0 +1,33 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecovery(t *testing.T) {
+	r := NewRouter()
+	r.GET("/panic", func(*Context) (any, error) {
+		panic("boom")
+	})
+	r.GET("/abort", func(*Context) (any, error) {
+		panic(http.ErrAbortHandler)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), `"status":"INTERNAL"`) {
+		t.Fatalf("GET /panic = %d %s", w.Code, w.Body)
+	}
+
+	// http.ErrAbortHandler aborts the response by net/http
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/abort")
+	if err == nil {
+		resp.Body.Close()
+		t.Fatalf("GET /abort = %d, want aborted", resp.StatusCode)
+	}
+}
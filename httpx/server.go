@@ -11,10 +11,15 @@ import (
 	"github.com/charliego3/pallas/utility"
 )
 
+// HealthzURI is the uri of HealthzHandler.
+//
+// Deprecated: it's not mounted, use the /livez and /readyz of health.Health
 const HealthzURI = "/debug/healthz"
 
 // HealthzHandler is a health-check handler that returns an OK status for all
 // incoming HTTP requests.
+//
+// Deprecated: it ignores draining, use the handlers of health.Health
 var HealthzHandler = func(w http.ResponseWriter, r *http.Request) {
 	_, _ = fmt.Fprint(w, "OK")
 }
@@ -53,7 +58,7 @@ func (h *Server) registerService(srv types.Service) {
 		if hd, ok := handler.(Handler); !ok {
 			panic(fmt.Sprintf("%T handler cannot register, expect: httpx.Handler", handler))
 		} else {
-			h.handle(m.Method, m.Template, hd)
+			h.HandleOperation(m.Operation, m.Method, m.Template, hd)
 		}
 	}
 }
@@ -77,6 +82,12 @@ func (h *Server) Run(ctx context.Context) error {
 	return h.Serve(h.Listener)
 }
 
+// Shutdown stops the server gracefully, the connections
+// are closed if ctx is done before they become idle
 func (h *Server) Shutdown(ctx context.Context) error {
-	return h.Server.Shutdown(ctx)
+	if err := h.Server.Shutdown(ctx); err != nil {
+		_ = h.Server.Close()
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,135 @@
+package httpx
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Template is a compiled google.api.http path template
+//
+//	Template = "/" Segments [ Verb ] ;
+//	Segments = Segment { "/" Segment } ;
+//	Segment  = "*" | "**" | LITERAL | Variable ;
+//	Variable = "{" FieldPath [ "=" Segments ] "}" ;
+//	FieldPath = IDENT { "." IDENT } ;
+//	Verb     = ":" LITERAL ;
+type Template struct {
+	// Raw is the original template, eg: /v1/{name=shelves/*/books/*}
+	Raw string
+
+	// Route is the gorilla/mux path template, eg: /v1/{name:shelves/[^/]+/books/[^/]+}
+	Route string
+
+	// Vars are the field paths of variables in order, eg: name, book.id
+	Vars []string
+
+	// Verb is the custom verb without colon
+	Verb string
+}
+
+const (
+	// anonymousVarPrefix is the route variable prefix of the wildcard segments
+	// which are not in a variable, they're not bound to request
+	anonymousVarPrefix = "_"
+
+	segmentPattern      = "[^/]+"
+	multiSegmentPattern = ".+"
+)
+
+var fieldPathRegexp = regexp.MustCompile(`^[A-Za-z_]\w*(\.[A-Za-z_]\w*)*$`)
+
+// CompileTemplate parse the google.api.http path template
+func CompileTemplate(template string) (*Template, error) {
+	if !strings.HasPrefix(template, "/") {
+		return nil, fmt.Errorf("template %q must start with /", template)
+	}
+
+	t := &Template{Raw: template}
+	path := template
+	if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "}") && i > strings.LastIndex(path, "/") {
+		path, t.Verb = path[:i], path[i+1:]
+	}
+
+	var route strings.Builder
+	anonymous := 0
+	for len(path) > 0 {
+		if path[0] != '/' {
+			return nil, fmt.Errorf("template %q: unexpected %q", template, path)
+		}
+		path = path[1:]
+		route.WriteByte('/')
+
+		if strings.HasPrefix(path, "{") {
+			end := strings.Index(path, "}")
+			if end == -1 {
+				return nil, fmt.Errorf("template %q: unbalanced braces", template)
+			}
+			fieldPath, segments, found := strings.Cut(path[1:end], "=")
+			if !fieldPathRegexp.MatchString(fieldPath) {
+				return nil, fmt.Errorf("template %q: invalid field path %q", template, fieldPath)
+			}
+			pattern := segmentPattern
+			if found {
+				var err error
+				if pattern, err = compileSegments(segments); err != nil {
+					return nil, fmt.Errorf("template %q: %w", template, err)
+				}
+			}
+			t.Vars = append(t.Vars, fieldPath)
+			route.WriteString("{" + fieldPath + ":" + pattern + "}")
+			path = path[end+1:]
+			continue
+		}
+
+		end := strings.IndexByte(path, '/')
+		if end == -1 {
+			end = len(path)
+		}
+		switch segment := path[:end]; segment {
+		case "*", "**":
+			pattern := segmentPattern
+			if segment == "**" {
+				pattern = multiSegmentPattern
+			}
+			route.WriteString("{" + anonymousVarPrefix + strconv.Itoa(anonymous) + ":" + pattern + "}")
+			anonymous++
+		case "":
+			return nil, fmt.Errorf("template %q: empty segment", template)
+		default:
+			if strings.ContainsAny(segment, "{}=") {
+				return nil, fmt.Errorf("template %q: invalid segment %q", template, segment)
+			}
+			route.WriteString(segment)
+		}
+		path = path[end:]
+	}
+
+	if len(t.Verb) > 0 {
+		route.WriteString(":" + t.Verb)
+	}
+	t.Route = route.String()
+	return t, nil
+}
+
+// compileSegments returns the regexp of variable segments, eg: shelves/*/books/*
+func compileSegments(segments string) (string, error) {
+	parts := strings.Split(segments, "/")
+	for i, part := range parts {
+		switch part {
+		case "*":
+			parts[i] = segmentPattern
+		case "**":
+			if i != len(parts)-1 {
+				return "", fmt.Errorf("** must be the last segment of %q", segments)
+			}
+			parts[i] = multiSegmentPattern
+		case "":
+			return "", fmt.Errorf("empty segment in %q", segments)
+		default:
+			parts[i] = regexp.QuoteMeta(part)
+		}
+	}
+	return strings.Join(parts, "/"), nil
+}
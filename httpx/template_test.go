@@ -0,0 +1,81 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/charliego3/pallas/middleware"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestCompileTemplate(t *testing.T) {
+	cases := []struct {
+		template string
+		route    string
+		verb     string
+	}{
+		{"/users/{id}", "/users/{id:[^/]+}", ""},
+		{"/users/{user.id}", "/users/{user.id:[^/]+}", ""},
+		{"/v1/{name=shelves/*/books/*}", "/v1/{name:shelves/[^/]+/books/[^/]+}", ""},
+		{"/v1/{name=files/**}", "/v1/{name:files/.+}", ""},
+		{"/v1/*/books/{id}:cancel", "/v1/{_0:[^/]+}/books/{id:[^/]+}:cancel", "cancel"},
+	}
+	for _, c := range cases {
+		tmpl, err := CompileTemplate(c.template)
+		if err != nil {
+			t.Fatalf("compile %s: %v", c.template, err)
+		}
+		if tmpl.Route != c.route || tmpl.Verb != c.verb {
+			t.Errorf("compile %s: got %s:%s, want %s:%s", c.template, tmpl.Route, tmpl.Verb, c.route, c.verb)
+		}
+	}
+
+	for _, template := range []string{"users/{id}", "/users/{id", "/v1/{name=**/books}", "/v1/{1d}"} {
+		if _, err := CompileTemplate(template); err == nil {
+			t.Errorf("compile %s: expect error", template)
+		}
+	}
+}
+
+func TestBindTemplateVars(t *testing.T) {
+	var got *descriptorpb.FileDescriptorProto
+	r := NewRouter()
+	r.HandleTemplate(http.MethodGet, "/files/{name}/{options.go_package=pkg/**}", func(ctx *Context) (any, error) {
+		got = new(descriptorpb.FileDescriptorProto)
+		return nil, ctx.BindVars(got)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/user.proto/pkg/a/b", nil))
+	if got == nil {
+		t.Fatalf("route not matched: %d", w.Code)
+	}
+	if got.GetName() != "user.proto" || got.GetOptions().GetGoPackage() != "pkg/a/b" {
+		t.Errorf("bind vars: got name=%q go_package=%q", got.GetName(), got.GetOptions().GetGoPackage())
+	}
+}
+
+func TestOperation(t *testing.T) {
+	var got []string
+	capture := func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			got = append(got, ctx.Operation+" "+ctx.Template)
+			return next(ctx)
+		}
+	}
+	handler := func(*Context) (any, error) { return nil, nil }
+
+	r := NewRouter(capture)
+	r.HandleOperation("/pb.User/Get", http.MethodGet, "/users/{id}", handler)
+	r.Group("/v1").GET("/ping", handler)
+
+	for _, path := range []string{"/users/1", "/v1/ping"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	want := []string{"/pb.User/Get /users/{id}", "/v1/ping /v1/ping"}
+	if !slices.Equal(got, want) {
+		t.Errorf("operations = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+package pallas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/charliego3/pallas/utility"
+)
+
+// DefaultHookTimeout is the timeout of Hook which not specified
+const DefaultHookTimeout = 15 * time.Second
+
+// HookFunc is the function of lifecycle Hook
+type HookFunc func(context.Context) error
+
+// Hook is a named pair of functions, OnStart is called before the servers
+// start and OnStop is called after the servers stopped. the OnStop is only
+// called when the OnStart is nil or succeed
+type Hook struct {
+	Name    string
+	OnStart HookFunc
+	OnStop  HookFunc
+
+	// Timeout is the timeout of each function, default is DefaultHookTimeout
+	Timeout time.Duration
+
+	// intercept is the deprecated StartInterceptor which derives the ctx
+	intercept StartInterceptor
+}
+
+// OnStart append a start hook, the timeout is optional
+func (app *Application) OnStart(name string, fn HookFunc, timeout ...time.Duration) {
+	app.lifecycle.append(Hook{Name: name, OnStart: fn, Timeout: utility.First(0, timeout)})
+}
+
+// OnStop append a stop hook, the timeout is optional.
+// the stop hooks are called in reverse order
+func (app *Application) OnStop(name string, fn HookFunc, timeout ...time.Duration) {
+	app.lifecycle.append(Hook{Name: name, OnStop: fn, Timeout: utility.First(0, timeout)})
+}
+
+// Append add the hooks to Application lifecycle
+func (app *Application) Append(hooks ...Hook) {
+	app.lifecycle.append(hooks...)
+}
+
+// lifecycle runs the start hooks in order and stop hooks in reverse
+type lifecycle struct {
+	mu     sync.Mutex
+	hooks  []Hook
+	logger *slog.Logger
+
+	// started is the number of hooks which started
+	started int
+}
+
+func (l *lifecycle) append(hooks ...Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hooks...)
+}
+
+// start runs the OnStart of hooks in order, the started hooks
+// are stopped in reverse if any of them failed. the hooks run
+// without the lock so that they can append hooks, returns the ctx
+// derived by the deprecated StartInterceptor hooks
+func (l *lifecycle) start(ctx context.Context) (context.Context, error) {
+	for {
+		l.mu.Lock()
+		if l.started >= len(l.hooks) {
+			l.mu.Unlock()
+			return ctx, nil
+		}
+		hook := l.hooks[l.started]
+		l.mu.Unlock()
+
+		var err error
+		switch {
+		case hook.intercept != nil:
+			ctx, err = l.intercept(ctx, hook)
+		case hook.OnStart != nil:
+			err = l.run(ctx, "start", hook, hook.OnStart)
+		}
+		if err != nil {
+			return ctx, errors.Join(err, l.stop(ctx))
+		}
+
+		l.mu.Lock()
+		l.started++
+		l.mu.Unlock()
+	}
+}
+
+// intercept runs the StartInterceptor of hook, the derived
+// ctx is returned instead of ctx if it succeed
+func (l *lifecycle) intercept(ctx context.Context, hook Hook) (context.Context, error) {
+	derived := make(chan context.Context, 1)
+	err := l.run(ctx, "start", hook, func(context.Context) error {
+		// the interceptor receives ctx rather than the timeout ctx
+		// of run, which is canceled once the hook returned
+		c, err := hook.intercept(ctx)
+		derived <- c
+		return err
+	})
+	if err != nil {
+		return ctx, err
+	}
+	return utility.DObj(<-derived, ctx), nil
+}
+
+// stop runs the OnStop of started hooks in reverse
+func (l *lifecycle) stop(ctx context.Context) error {
+	var errs []error
+	for {
+		l.mu.Lock()
+		if l.started == 0 {
+			l.mu.Unlock()
+			return errors.Join(errs...)
+		}
+		l.started--
+		hook := l.hooks[l.started]
+		l.mu.Unlock()
+
+		if hook.OnStop != nil {
+			errs = append(errs, l.run(ctx, "stop", hook, hook.OnStop))
+		}
+	}
+}
+
+// run calls fn with the hook timeout, it returns when
+// the timeout exceeded even though fn is not returned
+func (l *lifecycle) run(ctx context.Context, phase string, hook Hook, fn HookFunc) error {
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	begin := time.Now()
+	errC := make(chan error, 1)
+	go func() {
+		errC <- fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errC:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	elapsed := time.Since(begin)
+	if err != nil {
+		l.logger.Error("[Lifecycle] "+phase+" hook failed", slog.String("name", hook.Name),
+			slog.Duration("elapsed", elapsed), slog.Any("err", err))
+		return fmt.Errorf("%s hook %q: %w", phase, hook.Name, err)
+	}
+	l.logger.Info("[Lifecycle] "+phase+" hook", slog.String("name", hook.Name), slog.Duration("elapsed", elapsed))
+	return nil
+}
@@ -0,0 +1,131 @@
+package pallas
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/charliego3/pallas/utility"
+)
+
+func TestLifecycle(t *testing.T) {
+	var calls []string
+	record := func(name string, err error) HookFunc {
+		return func(context.Context) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	l := &lifecycle{logger: slog.Default()}
+	l.append(
+		Hook{Name: "db", OnStart: record("start db", nil), OnStop: record("stop db", nil)},
+		Hook{Name: "cache", OnStop: record("stop cache", nil)},
+		Hook{Name: "consumer", OnStart: record("start consumer", errors.New("boom")), OnStop: record("stop consumer", nil)},
+		Hook{Name: "ticker", OnStart: record("start ticker", nil)},
+	)
+
+	if _, err := l.start(context.Background()); err == nil {
+		t.Fatal("start should fail")
+	}
+	want := []string{"start db", "start consumer", "stop cache", "stop db"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+
+	calls = nil
+	if err := l.stop(context.Background()); err != nil || len(calls) > 0 {
+		t.Fatalf("stop after rollback: %v %v", err, calls)
+	}
+}
+
+func TestHookTimeout(t *testing.T) {
+	l := &lifecycle{logger: slog.Default()}
+	l.append(Hook{
+		Name:    "slow",
+		Timeout: 10 * time.Millisecond,
+		OnStart: func(context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		},
+	})
+
+	begin := time.Now()
+	_, err := l.start(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 500*time.Millisecond {
+		t.Fatalf("start returned after %s", elapsed)
+	}
+}
+
+func TestHookAppend(t *testing.T) {
+	l := &lifecycle{logger: slog.Default()}
+	var calls []string
+	l.append(Hook{Name: "outer", OnStart: func(context.Context) error {
+		// the hooks appended by a running hook are started as well
+		l.append(Hook{Name: "inner", OnStart: func(context.Context) error {
+			calls = append(calls, "inner")
+			return nil
+		}})
+		calls = append(calls, "outer")
+		return nil
+	}})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := l.start(context.Background())
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("start is deadlocked")
+	}
+	if want := []string{"outer", "inner"}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestDeprecatedHooks(t *testing.T) {
+	type key struct{}
+	app := &Application{lifecycle: &lifecycle{logger: slog.Default()}}
+	var started *Application
+	utility.Apply(app,
+		WithBeforeStart(func(ctx context.Context) (context.Context, error) {
+			return context.WithValue(ctx, key{}, "derived"), nil
+		}),
+		OnStartup(func(a *Application) error {
+			started = a
+			return nil
+		}),
+	)
+
+	ctx, err := app.lifecycle.start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Value(key{}) != "derived" || started != app {
+		t.Fatalf("ctx value = %v, started = %v", ctx.Value(key{}), started)
+	}
+
+	// the failed interceptor stops the started hooks
+	app = &Application{lifecycle: &lifecycle{logger: slog.Default()}}
+	var stopped bool
+	utility.Apply(app,
+		WithHooks(Hook{Name: "db", OnStop: func(context.Context) error { stopped = true; return nil }}),
+		WithBeforeStart(func(ctx context.Context) (context.Context, error) {
+			return nil, errors.New("boom")
+		}),
+	)
+	if _, err = app.lifecycle.start(context.Background()); err == nil || !stopped {
+		t.Fatalf("err = %v, stopped = %v", err, stopped)
+	}
+}
@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"strings"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/utility"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// AuthorizationHeader carries the bearer token
+	AuthorizationHeader = "Authorization"
+
+	// DefaultAPIKeyHeader carries the api key
+	DefaultAPIKeyHeader = "X-API-Key"
+
+	bearerPrefix = "Bearer "
+)
+
+// the errorx reasons of the Unauthenticated errors
+const (
+	ReasonMissingCredentials = "MISSING_CREDENTIALS"
+	ReasonInvalidToken       = "INVALID_TOKEN"
+	ReasonTokenExpired       = "TOKEN_EXPIRED"
+	ReasonInvalidAPIKey      = "INVALID_API_KEY"
+)
+
+// Claims is the default claims of JWT, the authenticated
+// api keys are represented by Claims too
+type Claims struct {
+	jwt.RegisteredClaims
+
+	// Roles are the roles granted to the subject
+	Roles []string `json:"roles,omitempty"`
+
+	// Scope is the space-separated OAuth 2.0 scopes
+	Scope string `json:"scope,omitempty"`
+}
+
+// Scopes returns the scopes of Scope
+func (c *Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+type claimsKey struct{}
+
+// NewContext returns the context carries the claims
+func NewContext(ctx context.Context, claims jwt.Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext returns the claims of the authenticated call
+func FromContext(ctx context.Context) (jwt.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(jwt.Claims)
+	return claims, ok
+}
+
+// ClaimsFromContext returns the claims of type T, eg: *auth.Claims
+func ClaimsFromContext[T jwt.Claims](ctx context.Context) (T, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(T)
+	return claims, ok
+}
+
+// APIKeyFunc returns the claims of the api key, the error
+// rejects the call, the Unauthenticated is returned if it's not an errorx
+type APIKeyFunc func(ctx context.Context, key string) (jwt.Claims, error)
+
+// StaticAPIKeys returns APIKeyFunc of the fixed keys
+func StaticAPIKeys(keys map[string]*Claims) APIKeyFunc {
+	return func(_ context.Context, key string) (jwt.Claims, error) {
+		if claims, ok := keys[key]; ok {
+			return claims, nil
+		}
+		return nil, errorx.Unauthenticated(ReasonInvalidAPIKey, "invalid api key")
+	}
+}
+
+// Authenticator validates the bearer JWT or api key of the calls
+type Authenticator struct {
+	keyfunc      jwt.Keyfunc
+	parserOpts   []jwt.ParserOption
+	claims       func() jwt.Claims
+	apiKeyHeader string
+	apiKey       APIKeyFunc
+	public       []string
+	publicFn     func(operation string) bool
+}
+
+// WithKeyfunc specify the Keyfunc of JWT
+func WithKeyfunc(keyfunc jwt.Keyfunc) utility.Option[Authenticator] {
+	return utility.OptionFunc[Authenticator](func(a *Authenticator) {
+		a.keyfunc = keyfunc
+	})
+}
+
+// WithHMAC validates the HS256, HS384 and HS512 JWT with secret
+func WithHMAC(secret []byte) utility.Option[Authenticator] {
+	return utility.OptionFunc[Authenticator](func(a *Authenticator) {
+		a.keyfunc = func(*jwt.Token) (any, error) { return secret, nil }
+		a.parserOpts = append(a.parserOpts, jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}))
+	})
+}
+
+// WithPublicKey validates the RS, PS or ES JWT with *rsa.PublicKey or *ecdsa.PublicKey
+func WithPublicKey(key crypto.PublicKey) utility.Option[Authenticator] {
+	return utility.OptionFunc[Authenticator](func(a *Authenticator) {
+		a.keyfunc = func(*jwt.Token) (any, error) { return key, nil }
+	})
+}
+
+// WithJWKS validates the JWT with the key of kid in JWKS
+func WithJWKS(jwks *JWKS) utility.Option[Authenticator] {
+	return utility.OptionFunc[Authenticator](func(a *Authenticator) {
+		a.keyfunc = jwks.Keyfunc
+	})
+}
+
+// WithParserOptions specify the JWT parser options, eg: jwt.WithIssuer, jwt.WithAudience
+func WithParserOptions(opts ...jwt.ParserOption) utility.Option[Authenticator] {
+	return utility.OptionFunc[Authenticator](func(a *Authenticator) {
+		a.parserOpts = append(a.parserOpts, opts...)
+	})
+}
+
+// WithClaims specify the claims type of JWT, default is *Claims
+func WithClaims(fn func() jwt.Claims) utility.Option[Authenticator] {
+	return utility.OptionFunc[Authenticator](func(a *Authenticator) {
+		a.claims = fn
+	})
+}
+
+// WithAPIKey validates the api key of header, default header is
+// DefaultAPIKeyHeader if empty, it's checked if no bearer token
+func WithAPIKey(header string, fn APIKeyFunc) utility.Option[Authenticator] {
+	return utility.OptionFunc[Authenticator](func(a *Authenticator) {
+		a.apiKeyHeader = utility.DString(header, DefaultAPIKeyHeader)
+		a.apiKey = fn
+	})
+}
+
+// WithPublic specify the operations without authentication,
+// the operation ends with * matches the prefix, eg: /grpc.health.v1.Health/*,
+// see WithPublicFunc for the Public rules of authz
+func WithPublic(operations ...string) utility.Option[Authenticator] {
+	return utility.OptionFunc[Authenticator](func(a *Authenticator) {
+		a.public = append(a.public, operations...)
+	})
+}
+
+// WithPublicFunc specify the operations without authentication by fn, eg:
+// the Authorizer.IsPublic of the rules declared public by authz
+func WithPublicFunc(fn func(operation string) bool) utility.Option[Authenticator] {
+	return utility.OptionFunc[Authenticator](func(a *Authenticator) {
+		a.publicFn = fn
+	})
+}
+
+// New returns Authenticator with options
+func New(opts ...utility.Option[Authenticator]) *Authenticator {
+	a := &Authenticator{
+		claims: func() jwt.Claims { return new(Claims) },
+	}
+	utility.Apply(a, opts...)
+	return a
+}
+
+// Server returns the middleware authenticates the calls, the claims
+// are put into context, see FromContext, and the subject is set by
+// middleware.SetSubject, it returns Unauthenticated which is 401 for http
+func Server(opts ...utility.Option[Authenticator]) middleware.Middleware {
+	return New(opts...).Middleware()
+}
+
+// Middleware returns the middleware of Authenticator
+func (a *Authenticator) Middleware() middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			if a.isPublic(ctx.Operation) {
+				return next(ctx)
+			}
+
+			claims, err := a.Authenticate(ctx)
+			if err != nil {
+				ctx.ResHeader.Set("WWW-Authenticate", "Bearer")
+				return nil, err
+			}
+			ctx.Context = NewContext(ctx.Context, claims)
+			if subject, _ := claims.GetSubject(); subject != "" {
+				ctx.Context = middleware.SetSubject(ctx.Context, subject)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// Authenticate returns the claims of the bearer token or api key in header
+func (a *Authenticator) Authenticate(ctx *middleware.Context) (jwt.Claims, error) {
+	if token, ok := bearerToken(ctx.ReqHeader.Get(AuthorizationHeader)); ok && a.keyfunc != nil {
+		return a.parse(token)
+	}
+	if a.apiKey != nil {
+		if key := ctx.ReqHeader.Get(a.apiKeyHeader); key != "" {
+			claims, err := a.apiKey(ctx, key)
+			if err != nil {
+				if e := new(errorx.Error); errors.As(err, &e) {
+					return nil, e
+				}
+				return nil, errorx.Unauthenticated(ReasonInvalidAPIKey, "invalid api key").WithCause(err)
+			}
+			return claims, nil
+		}
+	}
+	return nil, errorx.Unauthenticated(ReasonMissingCredentials, "missing credentials")
+}
+
+func (a *Authenticator) parse(token string) (jwt.Claims, error) {
+	claims := a.claims()
+	if _, err := jwt.ParseWithClaims(token, claims, a.keyfunc, a.parserOpts...); err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errorx.Unauthenticated(ReasonTokenExpired, "token is expired").WithCause(err)
+		}
+		return nil, errorx.Unauthenticated(ReasonInvalidToken, "invalid token").WithCause(err)
+	}
+	return claims, nil
+}
+
+func (a *Authenticator) isPublic(operation string) bool {
+	if a.publicFn != nil && a.publicFn(operation) {
+		return true
+	}
+	for _, p := range a.public {
+		if prefix, ok := strings.CutSuffix(p, "*"); (ok && strings.HasPrefix(operation, prefix)) || p == operation {
+			return true
+		}
+	}
+	return false
+}
+
+func bearerToken(header string) (string, bool) {
+	if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(bearerPrefix):]), true
+}
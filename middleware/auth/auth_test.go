@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// call runs the client middleware then the server middleware,
+// returns the claims seen by handler
+func call(t *testing.T, client, server middleware.Middleware, operation string) (*Claims, error) {
+	t.Helper()
+	var got *Claims
+	handler := server(func(ctx *middleware.Context) (any, error) {
+		got, _ = ClaimsFromContext[*Claims](ctx)
+		if subject, _ := middleware.SubjectFromContext(ctx); got != nil && subject != got.Subject {
+			t.Errorf("subject = %q, want %q", subject, got.Subject)
+		}
+		return nil, nil
+	})
+	_, err := client(func(cctx *middleware.Context) (any, error) {
+		ctx := middleware.NewGRPCContext(context.Background(), operation, nil)
+		ctx.ReqHeader = cctx.ReqHeader
+		return handler(ctx)
+	})(middleware.NewGRPCClientContext(context.Background(), operation, nil))
+	return got, err
+}
+
+func TestHMAC(t *testing.T) {
+	secret := []byte("secret")
+	server := Server(
+		WithHMAC(secret),
+		WithAPIKey("", StaticAPIKeys(map[string]*Claims{
+			"k1": {RegisteredClaims: jwt.RegisteredClaims{Subject: "job"}},
+		})),
+		WithPublic("/grpc.health.v1.Health/*"),
+	)
+	signed := SignedToken(jwt.SigningMethodHS256, secret, func() *Claims {
+		return &Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "alice"}, Roles: []string{"admin"}}
+	}, time.Minute)
+
+	claims, err := call(t, Client(signed), server, "/pb.User/Login")
+	if err != nil || claims.Subject != "alice" || claims.Roles[0] != "admin" {
+		t.Fatalf("bearer: %v %+v", err, claims)
+	}
+
+	claims, err = call(t, ClientAPIKey("", "k1"), server, "/pb.User/Login")
+	if err != nil || claims.Subject != "job" {
+		t.Fatalf("api key: %v %+v", err, claims)
+	}
+
+	expired, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{RegisteredClaims: jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+	}}).SignedString(secret)
+	tests := []struct {
+		client middleware.Middleware
+		reason string
+	}{
+		{Client(StaticToken(expired)), ReasonTokenExpired},
+		{Client(StaticToken("garbage")), ReasonInvalidToken},
+		{ClientAPIKey("", "k2"), ReasonInvalidAPIKey},
+		{middleware.Chain(), ReasonMissingCredentials},
+	}
+	for _, tt := range tests {
+		_, err := call(t, tt.client, server, "/pb.User/Login")
+		if !errorx.IsUnauthenticated(err) || errorx.Reason(err) != tt.reason ||
+			errorx.HTTPStatus(err) != http.StatusUnauthorized {
+			t.Errorf("want %s, got %v", tt.reason, err)
+		}
+	}
+
+	if _, err := call(t, middleware.Chain(), server, "/grpc.health.v1.Health/Check"); err != nil {
+		t.Fatalf("public operation: %v", err)
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	encode := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+	jwks := fmt.Sprintf(`{"keys":[
+		{"kty":"RSA","kid":"rs","use":"sig","n":%q,"e":%q},
+		{"kty":"EC","kid":"es","crv":"P-256","x":%q,"y":%q}
+	]}`, encode(rsaKey.N), encode(big.NewInt(int64(rsaKey.E))), encode(ecKey.X), encode(ecKey.Y))
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, []byte(jwks), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := Server(WithJWKS(NewJWKSFile(path, 0)))
+	sign := func(method jwt.SigningMethod, kid string, key any) string {
+		token := jwt.NewWithClaims(method, &Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: kid}})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	for kid, token := range map[string]string{
+		"rs": sign(jwt.SigningMethodRS256, "rs", rsaKey),
+		"es": sign(jwt.SigningMethodES256, "es", ecKey),
+	} {
+		claims, err := call(t, Client(StaticToken(token)), server, "/pb.User/Login")
+		if err != nil || claims.Subject != kid {
+			t.Fatalf("%s: %v %+v", kid, err, claims)
+		}
+	}
+
+	// the rsa public key must not be accepted as hmac secret
+	forged := sign(jwt.SigningMethodHS256, "rs", []byte(encode(rsaKey.N)))
+	if _, err := call(t, Client(StaticToken(forged)), server, "/pb.User/Login"); !errorx.IsUnauthenticated(err) {
+		t.Fatalf("forged token: %v", err)
+	}
+}
+
+func TestJWKSReload(t *testing.T) {
+	var (
+		calls atomic.Int32
+		fail  atomic.Bool
+		block = make(chan struct{})
+	)
+	s := newJWKS(func(context.Context) ([]byte, error) {
+		if calls.Add(1) > 2 {
+			<-block
+		}
+		if fail.Load() {
+			return nil, errors.New("down")
+		}
+		return []byte(`{"keys":[{"kty":"oct","kid":"k","k":"c2VjcmV0"}]}`), nil
+	}, 0)
+	token := &jwt.Token{Header: map[string]any{"kid": "k"}}
+
+	// the failed loading at cold start is not retried on every request
+	fail.Store(true)
+	for i := 0; i < 10; i++ {
+		if _, err := s.Keyfunc(token); err == nil {
+			t.Fatal("expect error of the failed loading")
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("load is called %d times, want 1", n)
+	}
+
+	fail.Store(false)
+	s.attempted = time.Time{}
+	if _, err := s.Keyfunc(token); err != nil {
+		t.Fatal(err)
+	}
+
+	// the stale key is served immediately while refreshing in background
+	s.loaded = time.Now().Add(-2 * DefaultJWKSRefresh)
+	s.attempted = time.Time{}
+	for i := 0; i < 10; i++ {
+		if _, err := s.Keyfunc(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(block)
+	for s.refreshing.Load() {
+		time.Sleep(time.Millisecond)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("load is called %d times, want 3", n)
+	}
+}
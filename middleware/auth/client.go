@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenSource returns the token of the outgoing calls
+type TokenSource func(ctx context.Context) (string, error)
+
+// StaticToken returns TokenSource of the fixed token
+func StaticToken(token string) TokenSource {
+	return func(context.Context) (string, error) {
+		return token, nil
+	}
+}
+
+// SignedToken returns TokenSource signs the claims with key, the token
+// is cached and re-signed when the most of ttl elapsed, the claims
+// func should not set the ExpiresAt and IssuedAt which are set by ttl
+func SignedToken(method jwt.SigningMethod, key any, claims func() *Claims, ttl time.Duration) TokenSource {
+	var (
+		mu      sync.Mutex
+		token   string
+		renewAt time.Time
+	)
+	return func(context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		now := time.Now()
+		if token != "" && now.Before(renewAt) {
+			return token, nil
+		}
+
+		c := claims()
+		c.IssuedAt = jwt.NewNumericDate(now)
+		c.ExpiresAt = jwt.NewNumericDate(now.Add(ttl))
+		signed, err := jwt.NewWithClaims(method, c).SignedString(key)
+		if err != nil {
+			return "", err
+		}
+		token, renewAt = signed, now.Add(ttl*4/5)
+		return token, nil
+	}
+}
+
+// Client returns the middleware attaches the bearer token to the outgoing calls
+func Client(source TokenSource) middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			token, err := source(ctx)
+			if err != nil {
+				return nil, errorx.Unauthenticated(ReasonMissingCredentials, "token unavailable").WithCause(err)
+			}
+			ctx.ReqHeader.Set(AuthorizationHeader, bearerPrefix+token)
+			return next(ctx)
+		}
+	}
+}
+
+// ClientAPIKey returns the middleware attaches the api key to the outgoing
+// calls, default header is DefaultAPIKeyHeader if empty
+func ClientAPIKey(header, key string) middleware.Middleware {
+	if header == "" {
+		header = DefaultAPIKeyHeader
+	}
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			ctx.ReqHeader.Set(header, key)
+			return next(ctx)
+		}
+	}
+}
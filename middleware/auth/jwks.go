@@ -0,0 +1,230 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultJWKSRefresh is the interval of reloading JWKS
+	DefaultJWKSRefresh = time.Hour
+
+	// minJWKSReload is the min interval of reloading on unknown kid
+	minJWKSReload = time.Minute
+)
+
+// JWKS is the cached json web key set loaded from file or url,
+// it's reloaded after the refresh interval or on unknown kid. the
+// stale keys are served while refreshing in background, and the
+// reloading is attempted at most once per minJWKSReload even if failed
+type JWKS struct {
+	load       func(ctx context.Context) ([]byte, error)
+	refresh    time.Duration
+	group      singleflight.Group
+	refreshing atomic.Bool
+
+	mu        sync.RWMutex
+	keys      map[string]any
+	loaded    time.Time
+	attempted time.Time
+	err       error
+}
+
+// NewJWKSFile returns JWKS loaded from the file
+func NewJWKSFile(path string, refresh time.Duration) *JWKS {
+	return newJWKS(func(context.Context) ([]byte, error) {
+		return os.ReadFile(path)
+	}, refresh)
+}
+
+// NewJWKSURL returns JWKS loaded from the url, eg: https://issuer/.well-known/jwks.json
+func NewJWKSURL(url string, refresh time.Duration) *JWKS {
+	return newJWKS(func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetch jwks %s: %s", url, res.Status)
+		}
+		return io.ReadAll(res.Body)
+	}, refresh)
+}
+
+func newJWKS(load func(ctx context.Context) ([]byte, error), refresh time.Duration) *JWKS {
+	if refresh <= 0 {
+		refresh = DefaultJWKSRefresh
+	}
+	return &JWKS{load: load, refresh: refresh}
+}
+
+// Keyfunc returns the key of token by kid header
+func (s *JWKS) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	stale := time.Since(s.loaded) > s.refresh
+	recent := time.Since(s.attempted) < minJWKSReload
+	lastErr := s.err
+	s.mu.RUnlock()
+	if ok {
+		if stale && !recent && s.refreshing.CompareAndSwap(false, true) {
+			go func() {
+				defer s.refreshing.Store(false)
+				_, _, _ = s.group.Do("", func() (any, error) {
+					return nil, s.reload(context.Background())
+				})
+			}()
+		}
+		return key, nil
+	}
+	if recent {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, fmt.Errorf("jwks: unknown kid %q", kid)
+	}
+
+	_, err, _ := s.group.Do("", func() (any, error) {
+		return nil, s.reload(context.Background())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if key, ok = s.keys[kid]; !ok {
+		return nil, fmt.Errorf("jwks: unknown kid %q", kid)
+	}
+	return key, nil
+}
+
+// reload loads the keys and records the attempt, the previous keys
+// are kept if failed
+func (s *JWKS) reload(ctx context.Context) (err error) {
+	defer func() {
+		s.mu.Lock()
+		s.attempted, s.err = time.Now(), err
+		s.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	data, err := s.load(ctx)
+	if err != nil {
+		return fmt.Errorf("jwks: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.keys, s.loaded = keys, time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// parseJWKS returns the keys by kid, the keys not for signature are ignored
+func parseJWKS(data []byte) (map[string]any, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.key()
+		if err != nil {
+			return nil, fmt.Errorf("jwks: key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k jwk) key() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(k.K)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,272 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/charliego3/pallas/configx"
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/middleware/auth"
+	"github.com/charliego3/pallas/utility"
+	"gopkg.in/yaml.v3"
+)
+
+// Reason is the errorx reason of the denied calls
+const Reason = "PERMISSION_DENIED"
+
+// DefaultInterval is the interval of checking the policy file
+const DefaultInterval = 10 * time.Second
+
+// Authorizer checks the rule of operation against the claims put by the
+// auth middleware, the Policy can be replaced by Update or the watched
+// policy file at runtime, see Start.
+//
+// the auth middleware must be placed before the Middleware, and the
+// public rules are shared with it by IsPublic, eg:
+//
+//	a := authz.New(authz.WithRules(pb.User_AuthzRules))
+//	pallas.WithMiddleware(
+//		auth.Server(auth.WithJWKS(jwks), auth.WithPublicFunc(a.IsPublic)),
+//		a.Middleware(),
+//	)
+type Authorizer struct {
+	rules    map[string]Rule
+	policy   Policy
+	file     string
+	interval time.Duration
+	logger   *slog.Logger
+
+	compiled atomic.Pointer[compiled]
+	modTime  time.Time
+	stop     chan struct{}
+}
+
+// WithRules specify the rules declared by the proto method options,
+// eg: pb.User_AuthzRules, the rules of Policy take precedence
+func WithRules(rules ...map[string]Rule) utility.Option[Authorizer] {
+	return utility.OptionFunc[Authorizer](func(a *Authorizer) {
+		for _, rs := range rules {
+			for operation, rule := range rs {
+				a.rules[operation] = rule
+			}
+		}
+	})
+}
+
+// WithPolicy specify the initial Policy
+func WithPolicy(policy Policy) utility.Option[Authorizer] {
+	return utility.OptionFunc[Authorizer](func(a *Authorizer) {
+		a.policy = policy
+	})
+}
+
+// WithFile specify the yaml policy file which is reloaded once it's modified,
+// the interval is DefaultInterval if it's not positive
+func WithFile(path string, interval time.Duration) utility.Option[Authorizer] {
+	return utility.OptionFunc[Authorizer](func(a *Authorizer) {
+		if interval <= 0 {
+			interval = DefaultInterval
+		}
+		a.file, a.interval = path, interval
+	})
+}
+
+// WithLogger specify the logger of reloading, default is slog.Default()
+func WithLogger(logger *slog.Logger) utility.Option[Authorizer] {
+	return utility.OptionFunc[Authorizer](func(a *Authorizer) {
+		a.logger = logger
+	})
+}
+
+// New returns Authorizer with options, it panics if the Policy is invalid,
+// the failure of loading the policy file is logged and the Policy is kept
+func New(opts ...utility.Option[Authorizer]) *Authorizer {
+	a := &Authorizer{
+		rules:  make(map[string]Rule),
+		logger: slog.Default(),
+		stop:   make(chan struct{}),
+	}
+	utility.Apply(a, opts...)
+	if err := a.Update(a.policy); err != nil {
+		panic(err)
+	}
+	if a.file != "" {
+		if _, err := a.reload(); err != nil {
+			a.logger.Error("[Authz] load policy file failed", slog.String("file", a.file), slog.Any("err", err))
+		}
+	}
+	return a
+}
+
+// FromConfig returns Authorizer of configx.Authz, the options override the config
+func FromConfig(opts ...utility.Option[Authorizer]) (*Authorizer, error) {
+	cfg, err := configx.Fetch[configx.Authz]()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := compile(cfg.Default); err != nil {
+		return nil, err
+	}
+
+	base := []utility.Option[Authorizer]{WithPolicy(cfg.AuthzPolicy)}
+	if cfg.File != "" {
+		base = append(base, WithFile(cfg.File, cfg.Interval))
+	}
+	return New(append(base, opts...)...), nil
+}
+
+// Server returns the middleware of Authorizer with options
+func Server(opts ...utility.Option[Authorizer]) middleware.Middleware {
+	return New(opts...).Middleware()
+}
+
+// Update replaces the Policy, the calls in flight are not affected
+func (a *Authorizer) Update(policy Policy) error {
+	c, err := compile(policy.Default, a.rules, policy.Rules)
+	if err != nil {
+		return err
+	}
+	a.compiled.Store(c)
+	return nil
+}
+
+// Load replaces the Policy by the yaml file
+func (a *Authorizer) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("authz: %w", err)
+	}
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return fmt.Errorf("authz: %s: %w", path, err)
+	}
+	return a.Update(policy)
+}
+
+// reload loads the policy file if it's modified since last loaded
+func (a *Authorizer) reload() (bool, error) {
+	info, err := os.Stat(a.file)
+	if err != nil {
+		return false, fmt.Errorf("authz: %w", err)
+	}
+	if info.ModTime().Equal(a.modTime) {
+		return false, nil
+	}
+	if err := a.Load(a.file); err != nil {
+		return false, err
+	}
+	a.modTime = info.ModTime()
+	return true, nil
+}
+
+// Start watches the policy file until ctx is done or Stop is called,
+// the Authorizer is a types.Component, eg: pallas.WithComponent("authz", a)
+func (a *Authorizer) Start(ctx context.Context) error {
+	if a.file == "" {
+		select {
+		case <-ctx.Done():
+		case <-a.stop:
+		}
+		return nil
+	}
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-a.stop:
+			return nil
+		case <-ticker.C:
+		}
+
+		// the invalid policy is ignored and the previous one is kept
+		reloaded, err := a.reload()
+		if err != nil {
+			a.logger.ErrorContext(ctx, "[Authz] reload policy file failed", slog.String("file", a.file), slog.Any("err", err))
+		} else if reloaded {
+			a.logger.InfoContext(ctx, "[Authz] policy file reloaded", slog.String("file", a.file))
+		}
+	}
+}
+
+// Stop stops watching the policy file
+func (a *Authorizer) Stop(context.Context) error {
+	select {
+	case <-a.stop:
+	default:
+		close(a.stop)
+	}
+	return nil
+}
+
+// Middleware returns the middleware rejects the calls with PermissionDenied,
+// it's 403 for http, it must be placed after the auth middleware
+func (a *Authorizer) Middleware() middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			if err := a.Authorize(ctx); err != nil {
+				return nil, err
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// IsPublic reports whether the rule of operation is Public,
+// it's the auth.WithPublicFunc to skip authentication
+func (a *Authorizer) IsPublic(operation string) bool {
+	rule, ok := a.compiled.Load().match(operation)
+	return ok && rule.Public
+}
+
+// Authorize returns PermissionDenied if the call does not satisfy the rule of operation
+func (a *Authorizer) Authorize(ctx *middleware.Context) error {
+	c := a.compiled.Load()
+	rule, ok := c.match(ctx.Operation)
+	switch {
+	case !ok && c.allow, ok && rule.Public:
+		return nil
+	case !ok:
+		return denied(ctx.Operation)
+	}
+
+	claims, _ := auth.FromContext(ctx)
+	subject, _ := middleware.SubjectFromContext(ctx)
+	if claims == nil && subject == "" {
+		return denied(ctx.Operation)
+	}
+	if len(rule.Subjects) > 0 && !slices.Contains(rule.Subjects, subject) {
+		return denied(ctx.Operation)
+	}
+
+	attrs := &attributes{claims: claims}
+	if len(rule.Roles) > 0 && !containsAny(rule.Roles, attrs.strings("roles")) {
+		return denied(ctx.Operation)
+	}
+	if len(rule.Scopes) > 0 {
+		scopes := attrs.scopes()
+		for _, scope := range rule.Scopes {
+			if !slices.Contains(scopes, scope) {
+				return denied(ctx.Operation)
+			}
+		}
+	}
+	for key, values := range rule.Attributes {
+		if !containsAny(values, attrs.strings(key)) {
+			return denied(ctx.Operation)
+		}
+	}
+	return nil
+}
+
+func denied(operation string) error {
+	return errorx.PermissionDenied(Reason, "permission denied of %s", operation)
+}
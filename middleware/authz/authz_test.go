@@ -0,0 +1,166 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/middleware/auth"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type tenantClaims struct {
+	auth.Claims
+	Tenant string `json:"tenant"`
+}
+
+func authorize(a *Authorizer, operation string, claims jwt.Claims) error {
+	ctx := middleware.NewGRPCContext(context.Background(), operation, nil)
+	if claims != nil {
+		ctx.Context = auth.NewContext(ctx.Context, claims)
+		subject, _ := claims.GetSubject()
+		ctx.Context = middleware.SetSubject(ctx.Context, subject)
+	}
+	_, err := a.Middleware()(func(*middleware.Context) (any, error) {
+		return nil, nil
+	})(ctx)
+	return err
+}
+
+func TestAuthorize(t *testing.T) {
+	a := New(
+		WithRules(map[string]Rule{
+			"/pb.User/Login":  {Public: true},
+			"/pb.User/Delete": {Roles: []string{"admin"}},
+		}),
+		WithPolicy(Policy{Rules: map[string]Rule{
+			"/pb.User/Delete": {Roles: []string{"admin"}, Scopes: []string{"user:write"}},
+			"/pb.User/*":      {},
+			"/pb.Job/Run":     {Subjects: []string{"job"}},
+			"/pb.Tenant/*":    {Attributes: map[string][]string{"tenant": {"acme"}}},
+		}}),
+	)
+
+	user := &tenantClaims{Claims: auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "alice"}, Roles: []string{"user"}}}
+	admin := &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "bob"}, Roles: []string{"admin"}, Scope: "user:read user:write"}
+	readonly := &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "carol"}, Roles: []string{"admin"}, Scope: "user:read"}
+	job := &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "job"}}
+	acme := &tenantClaims{Claims: auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "dave"}}, Tenant: "acme"}
+
+	tests := []struct {
+		operation string
+		claims    jwt.Claims
+		allowed   bool
+	}{
+		{"/pb.User/Login", nil, true},
+		{"/pb.User/Profile", nil, false},
+		{"/pb.User/Profile", user, true},
+		{"/pb.User/Delete", user, false},
+		{"/pb.User/Delete", readonly, false},
+		{"/pb.User/Delete", admin, true},
+		{"/pb.Job/Run", job, true},
+		{"/pb.Job/Run", admin, false},
+		{"/pb.Tenant/Get", acme, true},
+		{"/pb.Tenant/Get", user, false},
+		{"/pb.Order/List", admin, false},
+	}
+	for _, tt := range tests {
+		err := authorize(a, tt.operation, tt.claims)
+		if tt.allowed && err != nil {
+			t.Errorf("%s %v: %v", tt.operation, tt.claims, err)
+		}
+		if !tt.allowed && (!errorx.IsPermissionDenied(err) || errorx.HTTPStatus(err) != http.StatusForbidden) {
+			t.Errorf("%s %v: want denied, got %v", tt.operation, tt.claims, err)
+		}
+	}
+
+	if err := a.Update(Policy{Default: DefaultAllow}); err != nil {
+		t.Fatal(err)
+	}
+	if err := authorize(a, "/pb.Order/List", nil); err != nil {
+		t.Fatalf("default allow: %v", err)
+	}
+	if err := authorize(a, "/pb.User/Delete", user); !errorx.IsPermissionDenied(err) {
+		t.Fatalf("proto rules are kept: %v", err)
+	}
+	if err := a.Update(Policy{Default: "maybe"}); err == nil {
+		t.Fatal("expect invalid default")
+	}
+}
+
+func TestPublic(t *testing.T) {
+	a := New(WithRules(map[string]Rule{
+		"/pb.User/Login":   {Public: true},
+		"/pb.User/Profile": {},
+	}))
+	handler := middleware.Chain(
+		auth.Server(auth.WithHMAC([]byte("secret")), auth.WithPublicFunc(a.IsPublic)),
+		a.Middleware(),
+	)(func(*middleware.Context) (any, error) {
+		return nil, nil
+	})
+	call := func(operation string) error {
+		_, err := handler(middleware.NewGRPCContext(context.Background(), operation, nil))
+		return err
+	}
+
+	// the public rule skips authentication without repeating auth.WithPublic
+	if err := call("/pb.User/Login"); err != nil {
+		t.Fatalf("public: %v", err)
+	}
+	if err := call("/pb.User/Profile"); !errorx.IsUnauthenticated(err) {
+		t.Fatalf("expect unauthenticated, got %v", err)
+	}
+
+	// the updated policy is shared as well
+	if err := a.Update(Policy{Rules: map[string]Rule{"/pb.User/Profile": {Public: true}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := call("/pb.User/Profile"); err != nil {
+		t.Fatalf("updated public: %v", err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "authz.yaml")
+	write := func(content string, mtime time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	write("rules:\n  /pb.User/*:\n    roles: [admin]\n", now)
+
+	a := New(WithFile(path, 10*time.Millisecond))
+	user := &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "alice"}, Roles: []string{"user"}}
+	if err := authorize(a, "/pb.User/Get", user); !errorx.IsPermissionDenied(err) {
+		t.Fatalf("expect denied, got %v", err)
+	}
+
+	go a.Start(context.Background())
+	defer a.Stop(context.Background())
+
+	// the invalid policy is ignored
+	write("default: maybe\n", now.Add(time.Second))
+	time.Sleep(50 * time.Millisecond)
+	if err := authorize(a, "/pb.User/Get", user); !errorx.IsPermissionDenied(err) {
+		t.Fatalf("expect denied by previous policy, got %v", err)
+	}
+
+	write("rules:\n  /pb.User/*:\n    roles: [admin, user]\n", now.Add(2*time.Second))
+	deadline := time.Now().Add(time.Second)
+	for authorize(a, "/pb.User/Get", user) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("policy file is not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,127 @@
+package authz
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charliego3/pallas/configx"
+	"github.com/charliego3/pallas/middleware/authz/rule"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// the decisions of the operations without rule
+const (
+	DefaultDeny  = "deny"
+	DefaultAllow = "allow"
+)
+
+// Rule is the requirements of an operation, see rule.Rule
+type Rule = rule.Rule
+
+// Policy is the rules by operation, see configx.AuthzPolicy
+type Policy = configx.AuthzPolicy
+
+type prefixRule struct {
+	prefix string
+	rule   Rule
+}
+
+// compiled is the immutable policy for matching
+type compiled struct {
+	allow    bool
+	exact    map[string]Rule
+	prefixes []prefixRule
+}
+
+// compile merges the rules, the latter rules override the former of same operation
+func compile(def string, rules ...map[string]Rule) (*compiled, error) {
+	c := &compiled{exact: make(map[string]Rule)}
+	switch def {
+	case "", DefaultDeny:
+	case DefaultAllow:
+		c.allow = true
+	default:
+		return nil, fmt.Errorf("authz: invalid default decision %q", def)
+	}
+
+	prefixes := make(map[string]Rule)
+	for _, rs := range rules {
+		for operation, rule := range rs {
+			if prefix, ok := strings.CutSuffix(operation, "*"); ok {
+				prefixes[prefix] = rule
+			} else {
+				c.exact[operation] = rule
+			}
+		}
+	}
+	for prefix, rule := range prefixes {
+		c.prefixes = append(c.prefixes, prefixRule{prefix: prefix, rule: rule})
+	}
+	slices.SortFunc(c.prefixes, func(a, b prefixRule) int {
+		return len(b.prefix) - len(a.prefix)
+	})
+	return c, nil
+}
+
+// match returns the exactly matched rule or the rule of longest prefix
+func (c *compiled) match(operation string) (Rule, bool) {
+	if rule, ok := c.exact[operation]; ok {
+		return rule, true
+	}
+	for _, p := range c.prefixes {
+		if strings.HasPrefix(operation, p.prefix) {
+			return p.rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// attributes is the claims decoded as json object lazily
+type attributes struct {
+	claims jwt.Claims
+	values map[string]any
+}
+
+// strings returns the claim of key as strings, the array claim is flattened
+func (a *attributes) strings(key string) []string {
+	if a.values == nil {
+		a.values = make(map[string]any)
+		if data, err := json.Marshal(a.claims); err == nil {
+			_ = json.Unmarshal(data, &a.values)
+		}
+	}
+
+	switch v := a.values[key].(type) {
+	case nil:
+		return nil
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, e := range v {
+			values = append(values, fmt.Sprint(e))
+		}
+		return values
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// scopes returns the space-separated scope claim and the scp claim
+func (a *attributes) scopes() []string {
+	var scopes []string
+	for _, s := range a.strings("scope") {
+		scopes = append(scopes, strings.Fields(s)...)
+	}
+	return append(scopes, a.strings("scp")...)
+}
+
+// containsAny reports whether any of values is in set
+func containsAny(set, values []string) bool {
+	for _, v := range values {
+		if slices.Contains(set, v) {
+			return true
+		}
+	}
+	return false
+}
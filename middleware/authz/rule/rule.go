@@ -0,0 +1,22 @@
+// Package rule declares the authorization Rule without dependencies,
+// the generated *_authz.pb.go files refer to it rather than authz
+package rule
+
+// Rule is the requirements of an operation, all of the
+// non-empty requirements must be satisfied
+type Rule struct {
+	// Public allows all the calls even if unauthenticated
+	Public bool `json:"public,omitempty" yaml:"public,omitempty"`
+
+	// Roles requires any of the roles
+	Roles []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+
+	// Scopes requires all of the scopes
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+
+	// Subjects requires any of the subjects
+	Subjects []string `json:"subjects,omitempty" yaml:"subjects,omitempty"`
+
+	// Attributes requires the claim of each key is any of the values, eg: tenant: [acme]
+	Attributes map[string][]string `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
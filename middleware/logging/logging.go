@@ -1,7 +1,6 @@
 package logging
 
 import (
-	"context"
 	"github.com/charliego3/pallas/utility"
 	"log/slog"
 	"runtime/debug"
@@ -17,6 +16,7 @@ func Server(logger *slog.Logger) middleware.Middleware {
 			reply, err := next(ctx)
 			attrs := []any{
 				slog.String("kind", string(ctx.Kind)),
+				slog.String("operation", ctx.Operation),
 				slog.String("path", ctx.Path),
 				slog.Any("req", ctx.Payload),
 			}
@@ -38,7 +38,7 @@ func Server(logger *slog.Logger) middleware.Middleware {
 				)
 			}
 			attrs = append(attrs, slog.Duration("took", time.Since(startTime)))
-			logger.Log(context.Background(), level, "request", attrs...)
+			logger.Log(ctx, level, "request", attrs...)
 			return reply, err
 		}
 	}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"net"
+	"sync"
+)
+
+// Listener returns the listener counts the accepted and active
+// connections, eg: the cmux root listener of Application
+func (m *Metrics) Listener(l net.Listener) net.Listener {
+	return &listener{Listener: l, m: m}
+}
+
+type listener struct {
+	net.Listener
+	m *Metrics
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	l.m.accepted.Inc()
+	l.m.connections.Inc()
+	return &trackedConn{Conn: conn, m: l.m}, nil
+}
+
+type trackedConn struct {
+	net.Conn
+	m    *Metrics
+	once sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(c.m.connections.Dec)
+	return c.Conn.Close()
+}
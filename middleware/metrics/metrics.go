@@ -0,0 +1,210 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/utility"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/protobuf/proto"
+)
+
+// URI is the path of metrics handler
+const URI = "/metrics"
+
+// Metrics records the prometheus metrics of calls and connections,
+// the calls are labeled by kind, operation and code, the code is
+// grpc code name for grpc and http status for http
+type Metrics struct {
+	namespace  string
+	buckets    []float64
+	registerer prometheus.Registerer
+	gatherer   prometheus.Gatherer
+
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	inflight     *prometheus.GaugeVec
+	requestSize  *prometheus.HistogramVec
+	responseSize *prometheus.HistogramVec
+
+	accepted    prometheus.Counter
+	connections prometheus.Gauge
+	streams     *prometheus.GaugeVec
+	messages    *prometheus.CounterVec
+}
+
+// WithNamespace specify the namespace of metrics, default is pallas
+func WithNamespace(namespace string) utility.Option[Metrics] {
+	return utility.OptionFunc[Metrics](func(m *Metrics) {
+		m.namespace = namespace
+	})
+}
+
+// WithBuckets specify the buckets of duration histogram in seconds,
+// default is prometheus.DefBuckets
+func WithBuckets(buckets ...float64) utility.Option[Metrics] {
+	return utility.OptionFunc[Metrics](func(m *Metrics) {
+		m.buckets = buckets
+	})
+}
+
+// WithRegistry register the metrics to the registry and gather
+// from it, default is prometheus.DefaultRegisterer
+func WithRegistry(registry *prometheus.Registry) utility.Option[Metrics] {
+	return utility.OptionFunc[Metrics](func(m *Metrics) {
+		m.registerer = registry
+		m.gatherer = registry
+	})
+}
+
+// New returns Metrics registered to the registry, the collectors
+// already registered with the same options are reused
+func New(opts ...utility.Option[Metrics]) *Metrics {
+	m := &Metrics{
+		namespace:  "pallas",
+		buckets:    prometheus.DefBuckets,
+		registerer: prometheus.DefaultRegisterer,
+		gatherer:   prometheus.DefaultGatherer,
+	}
+	utility.Apply(m, opts...)
+
+	sizeBuckets := prometheus.ExponentialBuckets(64, 4, 8)
+	m.requests = register(m.registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: m.namespace,
+		Name:      "requests_total",
+		Help:      "Total number of the completed calls.",
+	}, []string{"kind", "operation", "code"}))
+	m.duration = register(m.registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: m.namespace,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the completed calls in seconds.",
+		Buckets:   m.buckets,
+	}, []string{"kind", "operation", "code"}))
+	m.inflight = register(m.registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: m.namespace,
+		Name:      "requests_in_flight",
+		Help:      "Number of the calls in progress.",
+	}, []string{"kind", "operation"}))
+	m.requestSize = register(m.registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: m.namespace,
+		Name:      "request_size_bytes",
+		Help:      "Size of the proto request payloads in bytes.",
+		Buckets:   sizeBuckets,
+	}, []string{"kind", "operation"}))
+	m.responseSize = register(m.registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: m.namespace,
+		Name:      "response_size_bytes",
+		Help:      "Size of the proto response payloads in bytes.",
+		Buckets:   sizeBuckets,
+	}, []string{"kind", "operation"}))
+	m.accepted = register(m.registerer, prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: m.namespace,
+		Name:      "connections_accepted_total",
+		Help:      "Total number of the accepted connections.",
+	}))
+	m.connections = register(m.registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: m.namespace,
+		Name:      "connections_active",
+		Help:      "Number of the open connections.",
+	}))
+	m.streams = register(m.registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: m.namespace,
+		Name:      "grpc_streams_active",
+		Help:      "Number of the grpc streams in progress.",
+	}, []string{"operation"}))
+	m.messages = register(m.registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: m.namespace,
+		Name:      "grpc_stream_messages_total",
+		Help:      "Total number of the grpc stream messages.",
+	}, []string{"operation", "direction"}))
+	return m
+}
+
+func register[T prometheus.Collector](registerer prometheus.Registerer, c T) T {
+	if err := registerer.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(T)
+		}
+		panic(err)
+	}
+	return c
+}
+
+// Handler returns the http.Handler serves the gathered metrics,
+// it's mounted on admin server if enabled, otherwise the main router
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.gatherer, promhttp.HandlerOpts{})
+}
+
+// Middleware returns the middleware records the calls of both
+// server and client, the grpc stream messages are counted by hooks
+func (m *Metrics) Middleware() middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (reply any, err error) {
+			kind, operation := string(ctx.Kind), ctx.Operation
+			inflight := m.inflight.WithLabelValues(kind, operation)
+			inflight.Inc()
+			if ctx.Stream != nil {
+				// the client middlewares return once the stream created,
+				// so only the server streams are counted as active
+				if !ctx.Kind.IsClient() {
+					active := m.streams.WithLabelValues(operation)
+					active.Inc()
+					defer active.Dec()
+				}
+				m.countMessages(ctx)
+			}
+
+			start := time.Now()
+			defer func() {
+				inflight.Dec()
+				code := codeOf(ctx.Kind, err)
+				m.requests.WithLabelValues(kind, operation, code).Inc()
+				m.duration.WithLabelValues(kind, operation, code).Observe(time.Since(start).Seconds())
+				if size, ok := sizeOf(ctx.Payload); ok {
+					m.requestSize.WithLabelValues(kind, operation).Observe(size)
+				}
+				if size, ok := sizeOf(reply); ok && err == nil {
+					m.responseSize.WithLabelValues(kind, operation).Observe(size)
+				}
+			}()
+			return next(ctx)
+		}
+	}
+}
+
+// countMessages counts the received and sent messages of stream
+func (m *Metrics) countMessages(ctx *middleware.Context) {
+	received := m.messages.WithLabelValues(ctx.Operation, "received")
+	sent := m.messages.WithLabelValues(ctx.Operation, "sent")
+	ctx.Stream.OnRecv(func(*middleware.Context, any) error {
+		received.Inc()
+		return nil
+	})
+	ctx.Stream.OnSend(func(*middleware.Context, any) error {
+		sent.Inc()
+		return nil
+	})
+}
+
+func codeOf(kind middleware.Kind, err error) string {
+	switch kind {
+	case middleware.KindGRPC, middleware.KindGRPCClient:
+		return errorx.Code(err).String()
+	default:
+		return strconv.Itoa(errorx.HTTPStatus(err))
+	}
+}
+
+// sizeOf returns the encoded size of proto.Message
+func sizeOf(v any) (float64, bool) {
+	msg, ok := v.(proto.Message)
+	if !ok || msg == nil {
+		return 0, false
+	}
+	return float64(proto.Size(msg)), true
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestMiddleware(t *testing.T) {
+	m := New(WithRegistry(prometheus.NewRegistry()))
+	mw := m.Middleware()
+
+	grpcCtx := middleware.NewGRPCContext(context.Background(), "/pb.User/Login", wrapperspb.String("alice"))
+	_, _ = mw(func(*middleware.Context) (any, error) {
+		return nil, errorx.NotFound("USER_NOT_FOUND", "user not found")
+	})(grpcCtx)
+
+	httpCtx := middleware.NewHTTPContext(httptest.NewRequest(http.MethodPost, "/user/login", nil))
+	httpCtx.Operation = "/pb.User/Login"
+	_, _ = mw(func(*middleware.Context) (any, error) {
+		return wrapperspb.String("token"), nil
+	})(httpCtx)
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("GRPC", "/pb.User/Login", "NotFound")); got != 1 {
+		t.Fatalf("grpc requests = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("HTTP", "/pb.User/Login", "200")); got != 1 {
+		t.Fatalf("http requests = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.inflight.WithLabelValues("GRPC", "/pb.User/Login")); got != 0 {
+		t.Fatalf("in flight = %v, want 0", got)
+	}
+	if got := testutil.CollectAndCount(m.requestSize); got != 1 {
+		t.Fatalf("request size series = %d, want 1", got)
+	}
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, URI, nil))
+	if !strings.Contains(w.Body.String(), "pallas_request_duration_seconds_bucket") {
+		t.Fatalf("duration histogram not exposed:\n%s", w.Body.String())
+	}
+}
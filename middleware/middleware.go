@@ -12,19 +12,46 @@ type Kind string
 const (
 	KindHTTP Kind = "HTTP"
 	KindGRPC Kind = "GRPC"
+
+	// KindHTTPClient is the outgoing http call
+	KindHTTPClient Kind = "HTTP_CLIENT"
+
+	// KindGRPCClient is the outgoing grpc call
+	KindGRPCClient Kind = "GRPC_CLIENT"
 )
 
+// IsClient reports whether the kind is an outgoing call
+func (k Kind) IsClient() bool {
+	return k == KindHTTPClient || k == KindGRPCClient
+}
+
 type Context struct {
 	context.Context
-	Kind      Kind
-	Method    string
-	Path      string
+	Kind   Kind
+	Method string
+	Path   string
+
+	// Operation is the low-cardinality name of the call, it's the full
+	// grpc method for both the grpc call and its http binding, eg: /pb.User/Login,
+	// the plain http routes use the Template
+	Operation string
+
+	// Template is the route template of the call, eg: /user/{id},
+	// it's the full grpc method for grpc
+	Template string
+
 	ReqHeader Header
 	ResHeader Header
 	Payload   any
+
+	// Stream is the streaming call, nil for unary call
+	Stream *Stream
 }
 
-type requestKey struct{}
+type (
+	requestKey struct{}
+	subjectKey struct{}
+)
 
 type Handler func(ctx *Context) (any, error)
 
@@ -51,7 +78,10 @@ func NewHTTPContext(req *http.Request) *Context {
 	ctx.Kind = KindHTTP
 	ctx.Method = req.Method
 	ctx.Path = req.URL.Path
-	ctx.ReqHeader = Header(req.Header)
+	ctx.ReqHeader = make(Header, len(req.Header))
+	for k, v := range req.Header {
+		ctx.ReqHeader.Add(k, v...)
+	}
 	ctx.ResHeader = make(Header)
 	ctx.Payload = nil
 	return ctx
@@ -63,12 +93,46 @@ func NewGRPCContext(ctx context.Context, method string, req any) *Context {
 	gctx.Context = ctx
 	gctx.Kind = KindGRPC
 	gctx.Path = method
+	gctx.Operation = method
+	gctx.Template = method
 	gctx.ReqHeader = Header(header)
 	gctx.ResHeader = make(Header)
 	gctx.Payload = req
 	return gctx
 }
 
+// NewHTTPClientContext returns the outgoing http call Context, the Operation
+// is the method rather than the concrete path which is unbounded as a label
+func NewHTTPClientContext(ctx context.Context, method, path string, req any) *Context {
+	hctx := new(Context)
+	hctx.Context = ctx
+	hctx.Kind = KindHTTPClient
+	hctx.Method = method
+	hctx.Path = path
+	hctx.Operation = method
+	hctx.Template = path
+	hctx.ReqHeader = make(Header)
+	hctx.ResHeader = make(Header)
+	hctx.Payload = req
+	return hctx
+}
+
+// NewGRPCClientContext returns the outgoing call Context,
+// ReqHeader is copied from the outgoing metadata
+func NewGRPCClientContext(ctx context.Context, method string, req any) *Context {
+	header, _ := metadata.FromOutgoingContext(ctx)
+	gctx := new(Context)
+	gctx.Context = ctx
+	gctx.Kind = KindGRPCClient
+	gctx.Path = method
+	gctx.Operation = method
+	gctx.Template = method
+	gctx.ReqHeader = Header(header.Copy())
+	gctx.ResHeader = make(Header)
+	gctx.Payload = req
+	return gctx
+}
+
 func SetRequest(ctx context.Context, req *http.Request) context.Context {
 	return context.WithValue(ctx, requestKey{}, req)
 }
@@ -77,3 +141,15 @@ func RequestFromServerContext(ctx context.Context) (*http.Request, bool) {
 	req, ok := ctx.Value(requestKey{}).(*http.Request)
 	return req, ok
 }
+
+// SetSubject returns the context carries the authenticated subject,
+// eg: the user id of token, it's set by the auth middleware
+func SetSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// SubjectFromContext returns the authenticated subject of context
+func SubjectFromContext(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(subjectKey{}).(string)
+	return subject, ok
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Local is the in-process Limiter, the limits are held per replica
+type Local struct {
+	policy Policy
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*localBucket
+	swept   time.Time
+}
+
+type localBucket struct {
+	bucket
+	seen time.Time
+}
+
+// NewLocal returns the in-process Limiter of policy,
+// it panics if the policy is invalid
+func NewLocal(policy Policy) *Local {
+	mustValidate(policy, false)
+	return &Local{
+		policy:  policy,
+		now:     time.Now,
+		buckets: make(map[string]*localBucket),
+	}
+}
+
+func (l *Local) Allow(_ context.Context, key string) (bool, time.Duration, error) {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &localBucket{bucket: l.policy.newBucket()}
+		l.buckets[key] = b
+	}
+	b.seen = now
+	allowed, wait := b.take(now)
+	return allowed, wait, nil
+}
+
+// sweep removes the idle buckets at most once per ttl
+func (l *Local) sweep(now time.Time) {
+	ttl := l.policy.ttl()
+	if now.Sub(l.swept) < ttl {
+		return
+	}
+	l.swept = now
+	for key, b := range l.buckets {
+		if now.Sub(b.seen) >= ttl {
+			delete(l.buckets, key)
+		}
+	}
+}
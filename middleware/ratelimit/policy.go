@@ -0,0 +1,226 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Policy is the limiting algorithm of a key, it's evaluated
+// in process by Local and atomically by the script of Redis,
+// the script uses the TIME of redis rather than the clock of
+// replicas which may skew
+type Policy interface {
+	// newBucket returns the in-process state of a key
+	newBucket() bucket
+
+	// ttl is the duration the idle state of a key is kept
+	ttl() time.Duration
+
+	// eval takes a call of key on redis at the TIME of redis
+	eval(ctx context.Context, client redis.Scripter, key string) (bool, time.Duration, error)
+
+	// validate returns error if the policy never allows or divides by zero,
+	// the distributed policy is evaluated in milliseconds on redis
+	validate(distributed bool) error
+}
+
+// mustValidate panics with the invalid policy at the construction
+func mustValidate(policy Policy, distributed bool) {
+	if err := policy.validate(distributed); err != nil {
+		panic(fmt.Sprintf("ratelimit: %v", err))
+	}
+}
+
+// bucket takes a call at now, returns the duration
+// until the next call is allowed if denied
+type bucket interface {
+	take(now time.Time) (bool, time.Duration)
+}
+
+// TokenBucket allows Burst calls at once and refills Rate tokens per second
+type TokenBucket struct {
+	Rate  float64
+	Burst int
+}
+
+func (p TokenBucket) newBucket() bucket {
+	return &tokenBucket{policy: p, tokens: float64(p.Burst)}
+}
+
+func (p TokenBucket) validate(bool) error {
+	if !(p.Rate > 0) || math.IsInf(p.Rate, 1) {
+		return fmt.Errorf("invalid TokenBucket rate %v, it must be positive", p.Rate)
+	}
+	if p.Burst < 1 {
+		return fmt.Errorf("invalid TokenBucket burst %d, it must be at least 1", p.Burst)
+	}
+	return nil
+}
+
+func (p TokenBucket) ttl() time.Duration {
+	return time.Duration(float64(p.Burst)/p.Rate*float64(time.Second)) + time.Second
+}
+
+type tokenBucket struct {
+	policy TokenBucket
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) take(now time.Time) (bool, time.Duration) {
+	if !b.last.IsZero() {
+		elapsed := max(0, now.Sub(b.last).Seconds())
+		b.tokens = min(float64(b.policy.Burst), b.tokens+elapsed*b.policy.Rate)
+	}
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / b.policy.Rate * float64(time.Second))
+}
+
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local state = redis.call("HMGET", KEYS[1], "tokens", "last")
+local tokens = tonumber(state[1])
+local last = tonumber(state[2])
+if tokens == nil then
+	tokens = burst
+else
+	tokens = math.min(burst, tokens + math.max(0, now - last) * rate / 1000)
+end
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "last", tostring(now))
+redis.call("PEXPIRE", KEYS[1], ttl)
+return {allowed, wait}
+`)
+
+func (p TokenBucket) eval(ctx context.Context, client redis.Scripter, key string) (bool, time.Duration, error) {
+	return run(ctx, client, tokenBucketScript, []string{key},
+		p.Rate, p.Burst, p.ttl().Milliseconds())
+}
+
+// SlidingWindow allows Limit calls within the sliding Window, the count
+// is approximated by weighting the previous fixed window
+type SlidingWindow struct {
+	Limit  int
+	Window time.Duration
+}
+
+func (p SlidingWindow) newBucket() bucket {
+	return &slidingWindow{policy: p}
+}
+
+func (p SlidingWindow) validate(distributed bool) error {
+	if p.Limit < 1 {
+		return fmt.Errorf("invalid SlidingWindow limit %d, it must be at least 1", p.Limit)
+	}
+	if p.Window <= 0 || (distributed && p.Window < time.Millisecond) {
+		return fmt.Errorf("invalid SlidingWindow window %s, it must be positive and at least 1ms on redis", p.Window)
+	}
+	return nil
+}
+
+func (p SlidingWindow) ttl() time.Duration {
+	return 2 * p.Window
+}
+
+// allow reports whether a call is allowed with the counts of previous
+// and current window, elapsed is the duration since current window started
+func (p SlidingWindow) allow(prev, cur float64, elapsed time.Duration) (bool, time.Duration) {
+	window := float64(p.Window)
+	limit := float64(p.Limit)
+	if prev*(window-float64(elapsed))/window+cur+1 <= limit {
+		return true, 0
+	}
+	if cur+1 > limit || prev == 0 {
+		return false, p.Window - elapsed
+	}
+	// wait until enough of previous window slides out
+	wait := window - float64(elapsed) - (limit-1-cur)*window/prev
+	return false, time.Duration(math.Ceil(wait))
+}
+
+type slidingWindow struct {
+	policy    SlidingWindow
+	index     int64
+	prev, cur float64
+}
+
+func (w *slidingWindow) take(now time.Time) (bool, time.Duration) {
+	size := int64(w.policy.Window)
+	index := now.UnixNano() / size
+	switch {
+	case index == w.index+1:
+		w.prev, w.cur = w.cur, 0
+	case index != w.index:
+		w.prev, w.cur = 0, 0
+	}
+	w.index = index
+
+	elapsed := time.Duration(now.UnixNano() - index*size)
+	ok, wait := w.policy.allow(w.prev, w.cur, elapsed)
+	if ok {
+		w.cur++
+	}
+	return ok, wait
+}
+
+// slidingWindowScript keeps the index and counts of the windows in a hash
+// as slidingWindow does, the index is computed by the TIME of redis
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local index = math.floor(now / window)
+local elapsed = now - index * window
+local state = redis.call("HMGET", KEYS[1], "index", "prev", "cur")
+local last = tonumber(state[1])
+local prev, cur = tonumber(state[2]) or 0, tonumber(state[3]) or 0
+if last == index - 1 then
+	prev, cur = cur, 0
+elseif last ~= index then
+	prev, cur = 0, 0
+end
+local allowed, wait = 0, 0
+if prev * (window - elapsed) / window + cur + 1 <= limit then
+	cur = cur + 1
+	allowed = 1
+elseif cur + 1 > limit or prev == 0 then
+	wait = window - elapsed
+else
+	wait = math.ceil(window - elapsed - (limit - 1 - cur) * window / prev)
+end
+redis.call("HSET", KEYS[1], "index", tostring(index), "prev", tostring(prev), "cur", tostring(cur))
+redis.call("PEXPIRE", KEYS[1], window * 2)
+return {allowed, wait}
+`)
+
+func (p SlidingWindow) eval(ctx context.Context, client redis.Scripter, key string) (bool, time.Duration, error) {
+	return run(ctx, client, slidingWindowScript, []string{key}, p.Limit, p.Window.Milliseconds())
+}
+
+// run the script returns {allowed, wait milliseconds}
+func run(ctx context.Context, client redis.Scripter, script *redis.Script, keys []string, args ...any) (bool, time.Duration, error) {
+	res, err := script.Run(ctx, client, keys, args...).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
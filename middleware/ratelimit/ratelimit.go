@@ -0,0 +1,161 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/utility"
+	"google.golang.org/grpc/peer"
+)
+
+// Reason is the errorx reason of the rejected calls
+const Reason = "RATE_LIMITED"
+
+// Limiter decides whether the call of key is allowed,
+// retryAfter is the duration until next call allowed if denied
+type Limiter interface {
+	Allow(ctx context.Context, key string) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// KeyFunc returns the limiting key of the call,
+// the call is not limited if the key is empty
+type KeyFunc func(ctx *middleware.Context) string
+
+// ByOperation limits each operation, eg: /pb.User/Login
+func ByOperation() KeyFunc {
+	return func(ctx *middleware.Context) string {
+		return ctx.Operation
+	}
+}
+
+// ByIP limits each client ip of the remote address, use ByHeader
+// such as X-Real-IP if the service is behind the trusted proxies
+func ByIP() KeyFunc {
+	return func(ctx *middleware.Context) string {
+		var addr string
+		if req, ok := middleware.RequestFromServerContext(ctx); ok {
+			addr = req.RemoteAddr
+		} else if p, ok := peer.FromContext(ctx); ok {
+			addr = p.Addr.String()
+		}
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			return host
+		}
+		return addr
+	}
+}
+
+// ByHeader limits each value of the request header
+func ByHeader(name string) KeyFunc {
+	return func(ctx *middleware.Context) string {
+		return ctx.ReqHeader.Get(name)
+	}
+}
+
+// BySubject limits each authenticated subject, see middleware.SetSubject
+func BySubject() KeyFunc {
+	return func(ctx *middleware.Context) string {
+		subject, _ := middleware.SubjectFromContext(ctx)
+		return subject
+	}
+}
+
+// Join limits each combination of the keys, eg: operation per ip,
+// the call is not limited if any key is empty
+func Join(fns ...KeyFunc) KeyFunc {
+	return func(ctx *middleware.Context) string {
+		keys := make([]string, len(fns))
+		for i, fn := range fns {
+			if keys[i] = fn(ctx); keys[i] == "" {
+				return ""
+			}
+		}
+		return strings.Join(keys, "|")
+	}
+}
+
+// Ratelimit rejects the calls exceed the limits with ResourceExhausted
+type Ratelimit struct {
+	limiter    Limiter
+	key        KeyFunc
+	failClosed bool
+	logger     *slog.Logger
+}
+
+// WithKey specify the limiting key of calls, default is ByIP
+func WithKey(fn KeyFunc) utility.Option[Ratelimit] {
+	return utility.OptionFunc[Ratelimit](func(r *Ratelimit) {
+		r.key = fn
+	})
+}
+
+// WithFailClosed rejects the calls when the Limiter fails,
+// default the calls are allowed and the error is logged
+func WithFailClosed() utility.Option[Ratelimit] {
+	return utility.OptionFunc[Ratelimit](func(r *Ratelimit) {
+		r.failClosed = true
+	})
+}
+
+// WithLogger specify the logger of Limiter errors, default is slog.Default()
+func WithLogger(logger *slog.Logger) utility.Option[Ratelimit] {
+	return utility.OptionFunc[Ratelimit](func(r *Ratelimit) {
+		r.logger = logger
+	})
+}
+
+// Server returns the middleware limits the calls with limiter,
+// the rejected calls have Retry-After header and retry_after metadata
+// in seconds, it's 429 for http
+//
+//	ratelimit.Server(
+//		ratelimit.NewRedis(redisx.C(), ratelimit.TokenBucket{Rate: 10, Burst: 20}, ""),
+//		ratelimit.WithKey(ratelimit.Join(ratelimit.ByOperation(), ratelimit.ByIP())),
+//	)
+func Server(limiter Limiter, opts ...utility.Option[Ratelimit]) middleware.Middleware {
+	r := &Ratelimit{
+		limiter: limiter,
+		key:     ByIP(),
+		logger:  slog.Default(),
+	}
+	utility.Apply(r, opts...)
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			if err := r.allow(ctx); err != nil {
+				return nil, err
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func (r *Ratelimit) allow(ctx *middleware.Context) error {
+	key := r.key(ctx)
+	if key == "" {
+		return nil
+	}
+
+	allowed, retryAfter, err := r.limiter.Allow(ctx, key)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "[Ratelimit] limiter failed", slog.String("key", key), slog.Any("err", err))
+		if r.failClosed {
+			return errorx.Unavailable(Reason, "rate limiter unavailable").WithCause(err)
+		}
+		return nil
+	}
+	if allowed {
+		return nil
+	}
+
+	seconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+	ctx.ResHeader.Set("Retry-After", seconds)
+	return errorx.ResourceExhausted(Reason, "too many requests").
+		WithMetadata(map[string]string{"retry_after": seconds})
+}
@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/redis/go-redis/v9"
+)
+
+type step struct {
+	after   time.Duration
+	allowed bool
+	wait    time.Duration
+}
+
+var policies = []struct {
+	name   string
+	policy Policy
+	steps  []step
+}{
+	{
+		name:   "token bucket",
+		policy: TokenBucket{Rate: 2, Burst: 2},
+		steps: []step{
+			{0, true, 0},
+			{0, true, 0},
+			{0, false, 500 * time.Millisecond},
+			{250 * time.Millisecond, false, 250 * time.Millisecond},
+			{250 * time.Millisecond, true, 0},
+		},
+	},
+	{
+		name:   "sliding window",
+		policy: SlidingWindow{Limit: 2, Window: time.Second},
+		steps: []step{
+			{0, true, 0},
+			{0, true, 0},
+			{500 * time.Millisecond, false, 500 * time.Millisecond},
+			// 2 calls in previous window weighted by 0.75
+			{750 * time.Millisecond, false, 250 * time.Millisecond},
+			{250 * time.Millisecond, true, 0},
+		},
+	},
+}
+
+func TestLimiters(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	for _, p := range policies {
+		start := time.UnixMilli(1_700_000_000_000)
+		local := NewLocal(p.policy)
+		remote := NewRedis(client, p.policy, "test:"+p.name+":")
+		limiters := map[string]Limiter{"local": local, "redis": remote}
+
+		for backend, limiter := range limiters {
+			now := start
+			local.now = func() time.Time { return now }
+			for i, s := range p.steps {
+				now = now.Add(s.after)
+				// the redis backend uses the TIME of redis
+				mr.SetTime(now)
+				allowed, wait, err := limiter.Allow(context.Background(), "key")
+				if err != nil {
+					t.Fatalf("%s %s step %d: %v", p.name, backend, i, err)
+				}
+				if allowed != s.allowed || wait != s.wait {
+					t.Errorf("%s %s step %d: got %t %s, want %t %s",
+						p.name, backend, i, allowed, wait, s.allowed, s.wait)
+				}
+			}
+		}
+	}
+}
+
+func TestServer(t *testing.T) {
+	limiter := NewLocal(TokenBucket{Rate: 1, Burst: 1})
+	handler := Server(limiter, WithKey(ByHeader("X-Real-IP")))(func(*middleware.Context) (any, error) {
+		return "ok", nil
+	})
+
+	call := func() (*middleware.Context, error) {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("X-Real-IP", "10.0.0.1")
+		ctx := middleware.NewHTTPContext(req)
+		_, err := handler(ctx)
+		return ctx, err
+	}
+
+	if _, err := call(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := call()
+	if !errorx.IsResourceExhausted(err) || errorx.HTTPStatus(err) != http.StatusTooManyRequests {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ctx.ResHeader.Get("Retry-After"); got != "1" {
+		t.Fatalf("Retry-After = %q, want 1", got)
+	}
+}
+
+func TestInvalidPolicy(t *testing.T) {
+	tests := []struct {
+		policy Policy
+		redis  bool
+	}{
+		{TokenBucket{Rate: 0, Burst: 1}, false},
+		{TokenBucket{Rate: -1, Burst: 1}, false},
+		{TokenBucket{Rate: 1, Burst: 0}, false},
+		{SlidingWindow{Limit: 1, Window: 0}, false},
+		{SlidingWindow{Limit: 0, Window: time.Second}, false},
+		{SlidingWindow{Limit: 1, Window: time.Microsecond}, true},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%+v (redis %v) is accepted", tt.policy, tt.redis)
+				}
+			}()
+			if tt.redis {
+				NewRedis(nil, tt.policy, "")
+			} else {
+				NewLocal(tt.policy)
+			}
+		}()
+	}
+
+	// the sub-millisecond window is valid in process
+	NewLocal(SlidingWindow{Limit: 1, Window: time.Microsecond})
+}
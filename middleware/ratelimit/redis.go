@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisPrefix is the key prefix of Redis Limiter
+const DefaultRedisPrefix = "pallas:ratelimit:"
+
+// Redis is the distributed Limiter, the limits hold across replicas,
+// the state of a key is updated atomically by script at the TIME of
+// redis, it requires redis 5 or later which replicates the effects of script
+type Redis struct {
+	client redis.Scripter
+	policy Policy
+	prefix string
+}
+
+// NewRedis returns the distributed Limiter of policy, the keys are
+// prefixed with prefix, default is DefaultRedisPrefix if empty.
+// the client is usually redisx.C() which is configured by configx.Redis.
+// it panics if the policy is invalid
+func NewRedis(client redis.Scripter, policy Policy, prefix string) *Redis {
+	mustValidate(policy, true)
+	if prefix == "" {
+		prefix = DefaultRedisPrefix
+	}
+	return &Redis{
+		client: client,
+		policy: policy,
+		prefix: prefix,
+	}
+}
+
+func (r *Redis) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	return r.policy.eval(ctx, r.client, r.prefix+key)
+}
@@ -0,0 +1,100 @@
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/utility"
+)
+
+// HandlerFunc converts the recovered panic value to error
+type HandlerFunc func(ctx context.Context, p any) error
+
+// DefaultHandler returns errorx Internal error, the panic
+// value is not exposed to client but kept as the cause
+func DefaultHandler(_ context.Context, p any) error {
+	return errorx.Internal(errorx.UnknownReason, "internal server error").
+		WithCause(fmt.Errorf("panic: %v", p))
+}
+
+// Recoverer recovers the panic, logs the stack and converts it to error
+type Recoverer struct {
+	logger  *slog.Logger
+	handler HandlerFunc
+}
+
+// WithLogger specify the logger of panic stack, default is slog.Default()
+func WithLogger(logger *slog.Logger) utility.Option[Recoverer] {
+	return utility.OptionFunc[Recoverer](func(r *Recoverer) {
+		r.logger = logger
+	})
+}
+
+// WithHandler specify the handler which converts panic to error
+func WithHandler(handler HandlerFunc) utility.Option[Recoverer] {
+	return utility.OptionFunc[Recoverer](func(r *Recoverer) {
+		r.handler = handler
+	})
+}
+
+// New returns Recoverer with DefaultHandler
+func New(opts ...utility.Option[Recoverer]) *Recoverer {
+	r := &Recoverer{
+		logger:  slog.Default(),
+		handler: DefaultHandler,
+	}
+	utility.Apply(r, opts...)
+	return r
+}
+
+// Recover logs the stack and returns the error of handler,
+// it must be called in the deferred function
+//
+//	defer func() {
+//		if p := recover(); p != nil {
+//			err = r.Recover(ctx, p)
+//		}
+//	}()
+func (r *Recoverer) Recover(ctx context.Context, p any) error {
+	attrs := []any{
+		slog.Any("panic", p),
+		slog.String("stack", string(debug.Stack())),
+	}
+	if mctx, ok := ctx.(*middleware.Context); ok {
+		attrs = append(attrs, slog.String("kind", string(mctx.Kind)), slog.String("path", mctx.Path))
+		if utility.NonBlank(mctx.Method) {
+			attrs = append(attrs, slog.String("method", mctx.Method))
+		}
+	}
+	r.logger.ErrorContext(ctx, "[Recovery] panic recovered", attrs...)
+	return r.handler(ctx, p)
+}
+
+// Middleware returns the middleware which recovers the panic of next,
+// http.ErrAbortHandler of http handler is panicked again so that
+// net/http aborts the response without logging the stack
+func (r *Recoverer) Middleware() middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (reply any, err error) {
+			defer func() {
+				if p := recover(); p != nil {
+					if p == http.ErrAbortHandler && ctx.Kind == middleware.KindHTTP {
+						panic(p)
+					}
+					reply, err = nil, r.Recover(ctx, p)
+				}
+			}()
+			return next(ctx)
+		}
+	}
+}
+
+// Recovery returns the recovery middleware
+func Recovery(opts ...utility.Option[Recoverer]) middleware.Middleware {
+	return New(opts...).Middleware()
+}
@@ -0,0 +1,58 @@
+package recovery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+)
+
+func TestRecovery(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := func(*middleware.Context) (any, error) {
+		panic("boom")
+	}
+	ctx := &middleware.Context{Context: context.Background(), Kind: middleware.KindHTTP}
+
+	reply, err := Recovery(WithLogger(logger))(handler)(ctx)
+	if reply != nil || !errorx.IsInternal(err) {
+		t.Fatalf("reply = %v, err = %v", reply, err)
+	}
+
+	custom := errors.New("custom")
+	_, err = Recovery(WithLogger(logger), WithHandler(func(context.Context, any) error {
+		return custom
+	}))(handler)(ctx)
+	if err != custom {
+		t.Fatalf("err = %v, want %v", err, custom)
+	}
+}
+
+func TestAbortHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := func(*middleware.Context) (any, error) {
+		panic(http.ErrAbortHandler)
+	}
+
+	// the abort of http handler is not recovered
+	func() {
+		defer func() {
+			if p := recover(); p != http.ErrAbortHandler {
+				t.Fatalf("recovered = %v, want http.ErrAbortHandler", p)
+			}
+		}()
+		ctx := &middleware.Context{Context: context.Background(), Kind: middleware.KindHTTP}
+		_, _ = Recovery(WithLogger(logger))(handler)(ctx)
+		t.Fatal("http.ErrAbortHandler is recovered")
+	}()
+
+	ctx := &middleware.Context{Context: context.Background(), Kind: middleware.KindGRPC}
+	if _, err := Recovery(WithLogger(logger))(handler)(ctx); !errorx.IsInternal(err) {
+		t.Fatalf("grpc err = %v, want internal", err)
+	}
+}
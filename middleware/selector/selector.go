@@ -0,0 +1,150 @@
+package selector
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charliego3/pallas/middleware"
+)
+
+// MatchFunc reports whether the middlewares apply to the call
+type MatchFunc func(ctx *middleware.Context) bool
+
+// Builder selects the calls which the middlewares apply to,
+// the call is keyed by Context.Operation, eg: /pb.User/Login,
+// so one rule matches both the http binding and the grpc call
+// of the same rpc. the plain http routes are keyed by the route template,
+// the url paths of the http calls are matched by Path
+//
+//	selector.New(auth).
+//		Prefix("/pb.User/").
+//		Kind(middleware.KindHTTP, middleware.KindGRPC).
+//		Build()
+type Builder struct {
+	middlewares []middleware.Middleware
+
+	operations []string
+	prefixes   []string
+	paths      []string
+	regexps    []*regexp.Regexp
+	matchers   []MatchFunc
+	kinds      []middleware.Kind
+}
+
+// New returns Builder of the middlewares
+func New(middlewares ...middleware.Middleware) *Builder {
+	return &Builder{middlewares: middlewares}
+}
+
+// Operation matches the key exactly, eg: /pb.User/Login
+func (b *Builder) Operation(operations ...string) *Builder {
+	b.operations = append(b.operations, operations...)
+	return b
+}
+
+// Prefix matches the key with prefix, eg: /pb.User/, it's the
+// Operation rather than the url path of the http binding, see Path
+func (b *Builder) Prefix(prefixes ...string) *Builder {
+	b.prefixes = append(b.prefixes, prefixes...)
+	return b
+}
+
+// Path matches the url path or route template of the http calls,
+// the path ends with * matches the prefix, eg: /v1/users/*
+func (b *Builder) Path(paths ...string) *Builder {
+	b.paths = append(b.paths, paths...)
+	return b
+}
+
+// Regex matches the key with regular expression,
+// it panics if the expression cannot be compiled
+func (b *Builder) Regex(exprs ...string) *Builder {
+	for _, expr := range exprs {
+		b.regexps = append(b.regexps, regexp.MustCompile(expr))
+	}
+	return b
+}
+
+// Match matches the call with fn
+func (b *Builder) Match(fn ...MatchFunc) *Builder {
+	b.matchers = append(b.matchers, fn...)
+	return b
+}
+
+// Kind restricts the calls to the kinds, all kinds if absent
+func (b *Builder) Kind(kinds ...middleware.Kind) *Builder {
+	b.kinds = append(b.kinds, kinds...)
+	return b
+}
+
+// Build returns the middleware which applies the middlewares
+// to the selected calls, others are passed to next directly.
+// the call is selected if the kind is accepted and any rule of
+// Operation, Prefix, Path, Regex and Match matches, all calls of the
+// kinds are selected if there's no rule
+func (b *Builder) Build() middleware.Middleware {
+	chain := middleware.Chain(b.middlewares...)
+	return func(next middleware.Handler) middleware.Handler {
+		selected := chain(next)
+		return func(ctx *middleware.Context) (any, error) {
+			if b.matches(ctx) {
+				return selected(ctx)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func (b *Builder) matches(ctx *middleware.Context) bool {
+	if len(b.kinds) > 0 && !slices.Contains(b.kinds, ctx.Kind) {
+		return false
+	}
+	if len(b.operations)+len(b.prefixes)+len(b.paths)+len(b.regexps)+len(b.matchers) == 0 {
+		return true
+	}
+
+	key := Key(ctx)
+	if slices.Contains(b.operations, key) {
+		return true
+	}
+	for _, prefix := range b.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	if ctx.Kind == middleware.KindHTTP || ctx.Kind == middleware.KindHTTPClient {
+		for _, path := range b.paths {
+			if matchPath(path, ctx.Path) || matchPath(path, ctx.Template) {
+				return true
+			}
+		}
+	}
+	for _, re := range b.regexps {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	for _, fn := range b.matchers {
+		if fn(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// Key returns the Operation of the call, Path if it's empty
+func Key(ctx *middleware.Context) string {
+	if ctx.Operation != "" {
+		return ctx.Operation
+	}
+	return ctx.Path
+}
+
+// matchPath reports whether the path matches exactly or by the prefix before *
+func matchPath(pattern, path string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return pattern == path
+}
@@ -0,0 +1,125 @@
+package selector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/charliego3/pallas/httpx"
+	"github.com/charliego3/pallas/middleware"
+)
+
+func TestSelector(t *testing.T) {
+	var applied bool
+	mark := func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			applied = true
+			return next(ctx)
+		}
+	}
+	handler := func(*middleware.Context) (any, error) { return nil, nil }
+
+	tests := []struct {
+		name    string
+		builder *Builder
+		ctx     *middleware.Context
+		want    bool
+	}{
+		{
+			name:    "grpc operation",
+			builder: New(mark).Operation("/pb.User/Login"),
+			ctx:     middleware.NewGRPCContext(context.Background(), "/pb.User/Login", nil),
+			want:    true,
+		},
+		{
+			name:    "http binding operation",
+			builder: New(mark).Operation("/pb.User/Login"),
+			ctx:     &middleware.Context{Kind: middleware.KindHTTP, Path: "/user/login", Operation: "/pb.User/Login"},
+			want:    true,
+		},
+		{
+			name:    "prefix",
+			builder: New(mark).Prefix("/pb.Admin/"),
+			ctx:     middleware.NewGRPCContext(context.Background(), "/pb.User/Login", nil),
+		},
+		{
+			name:    "plain http path",
+			builder: New(mark).Regex(`^/static/.+\.js$`),
+			ctx:     &middleware.Context{Kind: middleware.KindHTTP, Path: "/static/app.js"},
+			want:    true,
+		},
+		{
+			name:    "prefix is not the url path",
+			builder: New(mark).Prefix("/v1/users/"),
+			ctx:     &middleware.Context{Kind: middleware.KindHTTP, Path: "/v1/users/1", Template: "/v1/users/{id}", Operation: "/pb.User/Get"},
+		},
+		{
+			name:    "path of http binding",
+			builder: New(mark).Path("/v1/users/*"),
+			ctx:     &middleware.Context{Kind: middleware.KindHTTP, Path: "/v1/users/1", Template: "/v1/users/{id}", Operation: "/pb.User/Get"},
+			want:    true,
+		},
+		{
+			name:    "path template",
+			builder: New(mark).Path("/v1/users/{id}"),
+			ctx:     &middleware.Context{Kind: middleware.KindHTTP, Path: "/v1/users/1", Template: "/v1/users/{id}", Operation: "/pb.User/Get"},
+			want:    true,
+		},
+		{
+			name:    "path is not grpc method",
+			builder: New(mark).Path("/pb.User/*"),
+			ctx:     middleware.NewGRPCContext(context.Background(), "/pb.User/Login", nil),
+		},
+		{
+			name:    "kind only",
+			builder: New(mark).Kind(middleware.KindHTTP),
+			ctx:     middleware.NewGRPCContext(context.Background(), "/pb.User/Login", nil),
+		},
+		{
+			name:    "kind and prefix",
+			builder: New(mark).Prefix("/pb.User/").Kind(middleware.KindGRPC),
+			ctx:     middleware.NewGRPCContext(context.Background(), "/pb.User/Login", nil),
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		applied = false
+		if _, err := tt.builder.Build()(handler)(tt.ctx); err != nil {
+			t.Fatal(err)
+		}
+		if applied != tt.want {
+			t.Errorf("%s: applied = %t, want %t", tt.name, applied, tt.want)
+		}
+	}
+}
+
+func TestPathRoute(t *testing.T) {
+	var applied []string
+	mark := func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			applied = append(applied, ctx.Path)
+			return next(ctx)
+		}
+	}
+
+	// the route is registered as the generated http binding does
+	r := httpx.NewRouter(New(mark).Path("/v1/users/*").Build())
+	r.HandleOperation("/pb.User/Get", http.MethodGet, "/v1/users/{id}", func(*httpx.Context) (any, error) {
+		return nil, nil
+	})
+	r.HandleOperation("/pb.Order/Get", http.MethodGet, "/v1/orders/{id}", func(*httpx.Context) (any, error) {
+		return nil, nil
+	})
+
+	for _, path := range []string{"/v1/users/1", "/v1/orders/1"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d", path, w.Code)
+		}
+	}
+	if len(applied) != 1 || applied[0] != "/v1/users/1" {
+		t.Fatalf("applied = %v, want /v1/users/1 only", applied)
+	}
+}
@@ -0,0 +1,155 @@
+package shedding
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/utility"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Reason is the errorx reason of the dropped calls
+const Reason = "OVERLOADED"
+
+// BBR drops the calls when the cpu usage exceeds the threshold and the
+// in-flight calls exceed the estimated capacity, the capacity is the max
+// passed per second multiplies the min latency of the window. the calls
+// keep being checked within the cooldown after dropped even if the cpu
+// usage decreased, so that the load does not bounce
+type BBR struct {
+	cpu       func() int64
+	threshold int64
+	cooldown  time.Duration
+	now       func() time.Time
+
+	inFlight atomic.Int64
+	dropped  atomic.Int64
+
+	mu     sync.Mutex
+	window *window
+
+	registerer prometheus.Registerer
+	drops      *prometheus.CounterVec
+}
+
+// WithCPUThreshold specify the cpu usage in permille which starts
+// dropping, default is 800
+func WithCPUThreshold(threshold int64) utility.Option[BBR] {
+	return utility.OptionFunc[BBR](func(b *BBR) {
+		b.threshold = threshold
+	})
+}
+
+// WithCPU specify the cpu usage in permille, default is CPUUsage
+func WithCPU(fn func() int64) utility.Option[BBR] {
+	return utility.OptionFunc[BBR](func(b *BBR) {
+		b.cpu = fn
+	})
+}
+
+// WithWindow specify the statistic window of n buckets, default is 10s of 100 buckets.
+// it panics if n is less than 2 or size is less than n nanoseconds
+func WithWindow(size time.Duration, n int) utility.Option[BBR] {
+	return utility.OptionFunc[BBR](func(b *BBR) {
+		b.window = newWindow(size, n)
+	})
+}
+
+// WithCooldown specify the duration of checking after dropped, default is 1s
+func WithCooldown(cooldown time.Duration) utility.Option[BBR] {
+	return utility.OptionFunc[BBR](func(b *BBR) {
+		b.cooldown = cooldown
+	})
+}
+
+// WithRegisterer specify the registerer of the dropped calls counter,
+// default is prometheus.DefaultRegisterer
+func WithRegisterer(registerer prometheus.Registerer) utility.Option[BBR] {
+	return utility.OptionFunc[BBR](func(b *BBR) {
+		b.registerer = registerer
+	})
+}
+
+// New returns BBR with options
+func New(opts ...utility.Option[BBR]) *BBR {
+	b := &BBR{
+		cpu:        CPUUsage,
+		threshold:  800,
+		cooldown:   time.Second,
+		now:        time.Now,
+		window:     newWindow(10*time.Second, 100),
+		registerer: prometheus.DefaultRegisterer,
+	}
+	utility.Apply(b, opts...)
+
+	b.drops = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "pallas",
+		Name:      "shedding_dropped_total",
+		Help:      "Total number of the calls dropped by load shedding.",
+	}, []string{"kind", "operation"})
+	if err := b.registerer.Register(b.drops); err != nil {
+		are, ok := err.(prometheus.AlreadyRegisteredError)
+		if !ok {
+			panic(err)
+		}
+		b.drops = are.ExistingCollector.(*prometheus.CounterVec)
+	}
+	return b
+}
+
+// Allow reports whether a call is allowed, the done must be
+// called after the allowed call completed
+func (b *BBR) Allow() (done func(), ok bool) {
+	now := b.now()
+	if b.shouldDrop(now) {
+		b.dropped.Store(now.UnixNano())
+		return nil, false
+	}
+
+	b.inFlight.Add(1)
+	return func() {
+		end := b.now()
+		b.inFlight.Add(-1)
+		b.mu.Lock()
+		b.window.add(end, end.Sub(now))
+		b.mu.Unlock()
+	}, true
+}
+
+func (b *BBR) shouldDrop(now time.Time) bool {
+	if b.cpu() < b.threshold {
+		dropped := b.dropped.Load()
+		if dropped == 0 || now.Sub(time.Unix(0, dropped)) > b.cooldown {
+			return false
+		}
+	}
+
+	b.mu.Lock()
+	maxInFlight := b.window.maxInFlight(now)
+	b.mu.Unlock()
+	return b.inFlight.Load() >= maxInFlight
+}
+
+// Middleware returns the middleware drops the calls with Unavailable,
+// it's 503 for http so that the clients retry on other instances
+func (b *BBR) Middleware() middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (any, error) {
+			done, ok := b.Allow()
+			if !ok {
+				b.drops.WithLabelValues(string(ctx.Kind), ctx.Operation).Inc()
+				return nil, errorx.Unavailable(Reason, "server is overloaded")
+			}
+			defer done()
+			return next(ctx)
+		}
+	}
+}
+
+// Server returns the middleware of BBR with options
+func Server(opts ...utility.Option[BBR]) middleware.Middleware {
+	return New(opts...).Middleware()
+}
@@ -0,0 +1,91 @@
+package shedding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBBR(t *testing.T) {
+	cpu := int64(900)
+	now := time.UnixMilli(1_700_000_000_000)
+	b := New(
+		WithCPU(func() int64 { return cpu }),
+		WithWindow(time.Second, 10),
+		WithRegisterer(prometheus.NewRegistry()),
+	)
+	b.now = func() time.Time { return now }
+
+	// 10 calls of 100ms pass in each bucket, the capacity is 100/s * 100ms
+	for i := 0; i < 5; i++ {
+		var dones []func()
+		for j := 0; j < 10; j++ {
+			done, ok := b.Allow()
+			if !ok {
+				t.Fatalf("bucket %d call %d is dropped", i, j)
+			}
+			dones = append(dones, done)
+		}
+		now = now.Add(100 * time.Millisecond)
+		for _, done := range dones {
+			done()
+		}
+	}
+
+	var dones []func()
+	for i := 0; i < 10; i++ {
+		done, ok := b.Allow()
+		if !ok {
+			t.Fatalf("call %d is dropped under capacity", i)
+		}
+		dones = append(dones, done)
+	}
+
+	handler := b.Middleware()(func(*middleware.Context) (any, error) { return nil, nil })
+	ctx := &middleware.Context{Kind: middleware.KindGRPC, Operation: "/pb.User/Login"}
+	if _, err := handler(ctx); !errorx.IsUnavailable(err) {
+		t.Fatalf("expect dropped over capacity, got %v", err)
+	}
+	if got := testutil.ToFloat64(b.drops.WithLabelValues("GRPC", "/pb.User/Login")); got != 1 {
+		t.Fatalf("drops = %v, want 1", got)
+	}
+
+	// still checked within cooldown even if cpu decreased
+	cpu = 100
+	if _, ok := b.Allow(); ok {
+		t.Fatal("expect dropped within cooldown")
+	}
+	now = now.Add(2 * time.Second)
+	if _, ok := b.Allow(); !ok {
+		t.Fatal("expect allowed after cooldown with low cpu")
+	}
+	for _, done := range dones {
+		done()
+	}
+}
+
+func TestInvalidWindow(t *testing.T) {
+	tests := []struct {
+		size time.Duration
+		n    int
+	}{
+		{time.Second, 0},
+		{time.Second, -1},
+		{time.Second, 1},
+		{5 * time.Nanosecond, 10},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("window %s of %d buckets is accepted", tt.size, tt.n)
+				}
+			}()
+			New(WithWindow(tt.size, tt.n), WithRegisterer(prometheus.NewRegistry()))
+		}()
+	}
+}
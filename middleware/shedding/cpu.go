@@ -0,0 +1,46 @@
+package shedding
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	cpuInterval = 500 * time.Millisecond
+	cpuDecay    = 0.95
+)
+
+var (
+	cpuUsage atomic.Int64
+	cpuOnce  sync.Once
+)
+
+// CPUUsage returns the moving average of the process cpu usage in
+// permille of GOMAXPROCS, eg: 800 is 80%, the sampling is started
+// on first call, it's always 0 on the platforms without getrusage
+func CPUUsage() int64 {
+	cpuOnce.Do(func() {
+		go sampleCPU()
+	})
+	return cpuUsage.Load()
+}
+
+func sampleCPU() {
+	ticker := time.NewTicker(cpuInterval)
+	defer ticker.Stop()
+
+	lastCPU, lastTime := processCPUTime(), time.Now()
+	var usage float64
+	for now := range ticker.C {
+		cpu := processCPUTime()
+		elapsed := now.Sub(lastTime) * time.Duration(runtime.GOMAXPROCS(0))
+		if elapsed > 0 {
+			current := float64(cpu-lastCPU) / float64(elapsed) * 1000
+			usage = usage*cpuDecay + min(current, 1000)*(1-cpuDecay)
+			cpuUsage.Store(int64(usage))
+		}
+		lastCPU, lastTime = cpu, now
+	}
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package shedding
+
+import (
+	"syscall"
+	"time"
+)
+
+// processCPUTime returns the user and system cpu time of process
+func processCPUTime() time.Duration {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
@@ -0,0 +1,80 @@
+package shedding
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// bucket is the completed calls in a span of window
+type bucket struct {
+	passed int64
+	rtSum  time.Duration
+}
+
+// window is the rolling buckets of the completed calls,
+// the current bucket is excluded from statistics
+type window struct {
+	span    time.Duration
+	buckets []bucket
+	offset  int
+	start   time.Time
+}
+
+// newWindow returns the window of n buckets, it panics if n is less
+// than 2 since the current bucket is excluded, or the span is zero
+func newWindow(size time.Duration, n int) *window {
+	if n < 2 || size < time.Duration(n) {
+		panic(fmt.Sprintf("shedding: invalid window %s of %d buckets, "+
+			"the buckets must be at least 2 and not more than the nanoseconds of size", size, n))
+	}
+	return &window{
+		span:    size / time.Duration(n),
+		buckets: make([]bucket, n),
+	}
+}
+
+// advance moves the current bucket to now and resets the expired buckets
+func (w *window) advance(now time.Time) {
+	if w.start.IsZero() {
+		w.start = now
+		return
+	}
+	n := int(now.Sub(w.start) / w.span)
+	if n <= 0 {
+		return
+	}
+	for i := 1; i <= min(n, len(w.buckets)); i++ {
+		w.buckets[(w.offset+i)%len(w.buckets)] = bucket{}
+	}
+	w.offset = (w.offset + n) % len(w.buckets)
+	w.start = w.start.Add(time.Duration(n) * w.span)
+}
+
+func (w *window) add(now time.Time, rt time.Duration) {
+	w.advance(now)
+	b := &w.buckets[w.offset]
+	b.passed++
+	b.rtSum += rt
+}
+
+// maxInFlight is the max passed per second multiplies the min
+// average latency, it's the estimated capacity of concurrency,
+// returns math.MaxInt64 until there's enough statistics
+func (w *window) maxInFlight(now time.Time) int64 {
+	w.advance(now)
+	var maxPass int64
+	minRT := time.Duration(math.MaxInt64)
+	for i, b := range w.buckets {
+		if i == w.offset || b.passed == 0 {
+			continue
+		}
+		maxPass = max(maxPass, b.passed)
+		minRT = min(minRT, b.rtSum/time.Duration(b.passed))
+	}
+	if maxPass == 0 {
+		return math.MaxInt64
+	}
+	perSecond := float64(maxPass) * float64(time.Second) / float64(w.span)
+	return int64(math.Ceil(perSecond * max(minRT, time.Millisecond).Seconds()))
+}
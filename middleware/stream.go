@@ -0,0 +1,76 @@
+package middleware
+
+import "context"
+
+// MessageHook is called on each message of the stream,
+// the error aborts the stream
+type MessageHook func(ctx *Context, msg any) error
+
+// Stream is the streaming call of Context, the middlewares
+// register hooks to observe each received and sent message
+//
+//	if ctx.Stream != nil {
+//		ctx.Stream.OnRecv(func(ctx *middleware.Context, msg any) error {
+//			return limiter.Wait(ctx)
+//		})
+//	}
+type Stream struct {
+	// ClientStreams reports whether the client sends multiple messages
+	ClientStreams bool
+
+	// ServerStreams reports whether the server sends multiple messages
+	ServerStreams bool
+
+	recvHooks []MessageHook
+	sendHooks []MessageHook
+}
+
+// OnRecv register hooks which are called after a message received
+func (s *Stream) OnRecv(hooks ...MessageHook) {
+	s.recvHooks = append(s.recvHooks, hooks...)
+}
+
+// OnSend register hooks which are called before a message sent
+func (s *Stream) OnSend(hooks ...MessageHook) {
+	s.sendHooks = append(s.sendHooks, hooks...)
+}
+
+// Received calls the recv hooks in order, it's called by transport
+func (s *Stream) Received(ctx *Context, msg any) error {
+	return runHooks(s.recvHooks, ctx, msg)
+}
+
+// Sending calls the send hooks in order, it's called by transport
+func (s *Stream) Sending(ctx *Context, msg any) error {
+	return runHooks(s.sendHooks, ctx, msg)
+}
+
+func runHooks(hooks []MessageHook, ctx *Context, msg any) error {
+	for _, hook := range hooks {
+		if err := hook(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewGRPCStreamContext returns the Context of streaming call,
+// the Payload is nil since the messages are observed by Stream hooks
+func NewGRPCStreamContext(ctx context.Context, method string, clientStreams, serverStreams bool) *Context {
+	gctx := NewGRPCContext(ctx, method, nil)
+	gctx.Stream = &Stream{
+		ClientStreams: clientStreams,
+		ServerStreams: serverStreams,
+	}
+	return gctx
+}
+
+// NewGRPCClientStreamContext returns the Context of outgoing streaming call
+func NewGRPCClientStreamContext(ctx context.Context, method string, clientStreams, serverStreams bool) *Context {
+	gctx := NewGRPCClientContext(ctx, method, nil)
+	gctx.Stream = &Stream{
+		ClientStreams: clientStreams,
+		ServerStreams: serverStreams,
+	}
+	return gctx
+}
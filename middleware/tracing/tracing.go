@@ -0,0 +1,151 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/utility"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation name of the spans
+const TracerName = "github.com/charliego3/pallas/middleware/tracing"
+
+// Tracer starts the spans of calls, the span name is Context.Operation
+type Tracer struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+	tracer     trace.Tracer
+}
+
+// WithTracerProvider specify the TracerProvider, default is otel.GetTracerProvider()
+func WithTracerProvider(provider trace.TracerProvider) utility.Option[Tracer] {
+	return utility.OptionFunc[Tracer](func(t *Tracer) {
+		t.provider = provider
+	})
+}
+
+// WithPropagator specify the propagator of the headers,
+// default is W3C traceparent and baggage
+func WithPropagator(propagator propagation.TextMapPropagator) utility.Option[Tracer] {
+	return utility.OptionFunc[Tracer](func(t *Tracer) {
+		t.propagator = propagator
+	})
+}
+
+// New returns Tracer with options
+func New(opts ...utility.Option[Tracer]) *Tracer {
+	t := &Tracer{
+		provider: otel.GetTracerProvider(),
+		propagator: propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		),
+	}
+	utility.Apply(t, opts...)
+	t.tracer = t.provider.Tracer(TracerName)
+	return t
+}
+
+// Server returns the middleware starts server span for http and grpc,
+// the parent span and baggage are extracted from the request header
+func Server(opts ...utility.Option[Tracer]) middleware.Middleware {
+	t := New(opts...)
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (reply any, err error) {
+			parent := t.propagator.Extract(ctx.Context, carrier(ctx.ReqHeader))
+			span := t.start(ctx, parent, trace.SpanKindServer)
+			defer func() { finish(span, ctx, err) }()
+			return next(ctx)
+		}
+	}
+}
+
+// Client returns the middleware starts client span for the outgoing calls,
+// the span and baggage are injected into the request header
+func Client(opts ...utility.Option[Tracer]) middleware.Middleware {
+	t := New(opts...)
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx *middleware.Context) (reply any, err error) {
+			span := t.start(ctx, ctx.Context, trace.SpanKindClient)
+			t.propagator.Inject(ctx.Context, carrier(ctx.ReqHeader))
+			defer func() { finish(span, ctx, err) }()
+			return next(ctx)
+		}
+	}
+}
+
+// start the span with parent and replace the Context with the span context
+func (t *Tracer) start(ctx *middleware.Context, parent context.Context, kind trace.SpanKind) trace.Span {
+	name := ctx.Operation
+	if name == "" {
+		name = ctx.Path
+	}
+	spanCtx, span := t.tracer.Start(parent, name,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(attributes(ctx)...),
+	)
+	ctx.Context = spanCtx
+	return span
+}
+
+func attributes(ctx *middleware.Context) []attribute.KeyValue {
+	switch ctx.Kind {
+	case middleware.KindGRPC, middleware.KindGRPCClient:
+		service, method, _ := strings.Cut(strings.TrimPrefix(ctx.Operation, "/"), "/")
+		return []attribute.KeyValue{
+			semconv.RPCSystemGRPC,
+			semconv.RPCService(service),
+			semconv.RPCMethod(method),
+		}
+	default:
+		return []attribute.KeyValue{
+			semconv.HTTPMethod(ctx.Method),
+			semconv.HTTPRoute(ctx.Template),
+			semconv.URLPath(ctx.Path),
+		}
+	}
+}
+
+// finish records the status of errorx error and ends the span
+func finish(span trace.Span, ctx *middleware.Context, err error) {
+	defer span.End()
+	switch ctx.Kind {
+	case middleware.KindGRPC, middleware.KindGRPCClient:
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(errorx.Code(err))))
+	default:
+		span.SetAttributes(semconv.HTTPStatusCode(errorx.HTTPStatus(err)))
+	}
+	if err == nil {
+		return
+	}
+
+	e := errorx.FromError(err)
+	if e.Reason != errorx.UnknownReason {
+		span.SetAttributes(attribute.String("error.reason", e.Reason))
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, e.Message)
+}
+
+// carrier adapts middleware.Header to propagation.TextMapCarrier
+type carrier middleware.Header
+
+func (c carrier) Get(key string) string {
+	return middleware.Header(c).Get(key)
+}
+
+func (c carrier) Set(key, value string) {
+	middleware.Header(c).Set(key, value)
+}
+
+func (c carrier) Keys() []string {
+	return middleware.Header(c).Keys()
+}
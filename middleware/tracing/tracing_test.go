@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charliego3/pallas/errorx"
+	"github.com/charliego3/pallas/middleware"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPropagation(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	member, _ := baggage.NewMember("tenant", "acme")
+	bag, _ := baggage.New(member)
+
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil)))
+
+	var tenant string
+	server := Server(WithTracerProvider(provider))(func(ctx *middleware.Context) (any, error) {
+		tenant = baggage.FromContext(ctx).Member("tenant").Value()
+		logger.InfoContext(ctx, "login")
+		return nil, errorx.NotFound("USER_NOT_FOUND", "user not found")
+	})
+
+	client := Client(WithTracerProvider(provider))(func(ctx *middleware.Context) (any, error) {
+		req := httptest.NewRequest(ctx.Method, ctx.Path, nil)
+		for k, v := range ctx.ReqHeader {
+			req.Header[k] = v
+		}
+		sctx := middleware.NewHTTPContext(req)
+		sctx.Operation = "/pb.User/Login"
+		sctx.Template = "/user/login"
+		return server(sctx)
+	})
+
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+	_, err := client(middleware.NewHTTPClientContext(ctx, "POST", "/user/login", nil))
+	if !errorx.IsNotFound(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	ss, cs := spans[0], spans[1]
+	if ss.SpanKind != trace.SpanKindServer || cs.SpanKind != trace.SpanKindClient {
+		t.Fatalf("span kinds = %s, %s", ss.SpanKind, cs.SpanKind)
+	}
+	if ss.Parent.SpanID() != cs.SpanContext.SpanID() || ss.SpanContext.TraceID() != cs.SpanContext.TraceID() {
+		t.Fatal("server span is not the child of client span")
+	}
+	if ss.Name != "/pb.User/Login" || ss.Status.Code != codes.Error {
+		t.Fatalf("server span = %s %v", ss.Name, ss.Status)
+	}
+	if tenant != "acme" {
+		t.Fatalf("baggage tenant = %q, want acme", tenant)
+	}
+	if !strings.Contains(buf.String(), ss.SpanContext.TraceID().String()) {
+		t.Fatalf("trace id not logged: %s", buf.String())
+	}
+}
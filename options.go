@@ -4,18 +4,34 @@ import (
 	"context"
 	"log/slog"
 	"net"
+	"os"
+	"time"
 
+	"github.com/charliego3/pallas/admin"
 	"github.com/charliego3/pallas/grpcx"
+	"github.com/charliego3/pallas/health"
 	"github.com/charliego3/pallas/middleware"
+	"github.com/charliego3/pallas/middleware/metrics"
+	"github.com/charliego3/pallas/registry"
+	"github.com/charliego3/pallas/types"
 	"github.com/charliego3/pallas/utility"
 	"github.com/soheilhy/cmux"
 
 	"github.com/charliego3/pallas/httpx"
 )
 
-type StartInterceptor func(context.Context) (context.Context, error)
-
 type options struct {
+	// id, name, version and metadata describe the service instance
+	// which registered to the registry
+	id       string
+	name     string
+	version  string
+	metadata map[string]string
+
+	// endpoints override the instance endpoints
+	// default using the http and grpc listener address
+	endpoints []string
+
 	// http and grpc server both listen this address
 	// when gopts or hopts has not sepcity listener
 	listener net.Listener
@@ -30,13 +46,79 @@ type options struct {
 	// middles accept http server Middleware
 	hopts []utility.Option[httpx.Server]
 
-	// onStartup run on Applition after init
-	onStartup   func(*Application) error
-	beforeStart StartInterceptor
-	afterStart  StartInterceptor
+	// signals trigger the graceful shutdown, default is SIGINT and SIGTERM
+	signals []os.Signal
+
+	// shutdownTimeout is the maximum duration of graceful shutdown
+	shutdownTimeout time.Duration
+
+	// drainDelay is the duration to wait after draining
+	// before stop the servers, default is 0
+	drainDelay time.Duration
+
+	// components are started with servers and stopped after servers
+	components []*Supervisor
+
+	// adminAddr is the internal-only address of admin server,
+	// default using App.Admin in config, disabled if both blank
+	adminAddr string
+
+	// healthInterval is the interval of updating grpc health status
+	healthInterval time.Duration
+
+	// aopts is admin.Server options
+	aopts []utility.Option[admin.Server]
+
+	// metrics records the calls and connections, nil if disabled
+	metrics *metrics.Metrics
+}
+
+// WithID specify the service instance id, default is hostname with random suffix
+func WithID(id string) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.id = id
+	})
+}
 
-	beforeShutdown func(context.Context) error
-	afterShutdown  func(context.Context) error
+// WithName specify the service name, default is App.Name in config or executable name
+func WithName(name string) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.name = name
+	})
+}
+
+// WithVersion specify the service version, default is App.Version in config
+func WithVersion(version string) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.version = version
+	})
+}
+
+// WithMetadata add kv pair metadata to service instance
+func WithMetadata(md map[string]string) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		if app.metadata == nil {
+			app.metadata = make(map[string]string, len(md))
+		}
+		for k, v := range md {
+			app.metadata[k] = v
+		}
+	})
+}
+
+// WithEndpoints override the registered endpoints, eg: http://127.0.0.1:8000
+func WithEndpoints(endpoints ...string) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.endpoints = endpoints
+	})
+}
+
+// WithRegistry register service instance to registry after start
+// and unregister before shutdown
+func WithRegistry(r registry.Registry) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.registry = r
+	})
 }
 
 // WithGrpcMatcher custom grpc dispatcher
@@ -59,21 +141,119 @@ func WithLogger(logger *slog.Logger) utility.Option[Application] {
 	})
 }
 
-func WithBeforeStart(handler StartInterceptor) utility.Option[Application] {
+// WithSignals specify the signals which trigger the graceful shutdown,
+// the signal handling is disabled if no signal given
+func WithSignals(signals ...os.Signal) utility.Option[Application] {
 	return utility.OptionFunc[Application](func(app *Application) {
-		app.beforeStart = handler
+		app.signals = signals
 	})
 }
 
-func WithHttpOpts(hopts ...utility.Option[httpx.Server]) utility.Option[Application] {
-	return utility.OptionFunc[Application](func(cfg *Application) {
-		cfg.hopts = append(cfg.hopts, hopts...)
+// WithShutdownTimeout specify the maximum duration of graceful shutdown,
+// the in-flight requests are aborted after timeout, default is 30s
+func WithShutdownTimeout(timeout time.Duration) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.shutdownTimeout = timeout
+	})
+}
+
+// WithDrainDelay specify the duration to wait after the health check
+// fails and before the servers stop, it gives load balancers time
+// to remove this instance
+func WithDrainDelay(delay time.Duration) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.drainDelay = delay
+	})
+}
+
+// WithComponent register the Component which is started and stopped
+// together with the servers, the failure of component stops the
+// Application unless WithRestart specified
+func WithComponent(name string, c types.Component, opts ...utility.Option[Supervisor]) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.components = append(app.components, newSupervisor(name, c, opts...))
+	})
+}
+
+// WithAdminAddr serve the admin server on the internal-only TCP address,
+// it serves pprof, expvar, routes, grpc services, config, build info and health
+func WithAdminAddr(addr string) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.adminAddr = addr
+	})
+}
+
+// WithAdminOpts accept admin server options
+func WithAdminOpts(aopts ...utility.Option[admin.Server]) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.aopts = append(app.aopts, aopts...)
+	})
+}
+
+// WithMetrics records the http and grpc calls and the connections of
+// the listeners, the /metrics is served on the admin server if enabled,
+// otherwise on the main http router. the metrics middleware is the
+// outermost one just inside recovery regardless of the option order,
+// so the calls rejected by auth, ratelimit or shedding are recorded
+func WithMetrics(m *metrics.Metrics) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.metrics = m
+		app.hopts = append([]utility.Option[httpx.Server]{httpx.WithMiddleware(m.Middleware())}, app.hopts...)
+		app.gopts = append([]utility.Option[grpcx.Server]{grpcx.WithMiddleware(m.Middleware())}, app.gopts...)
+	})
+}
+
+// WithHealthCheck register the named check, see health.Health.Register
+func WithHealthCheck(name string, fn health.CheckFunc, opts ...utility.Option[health.Check]) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.health.Register(name, fn, opts...)
 	})
 }
 
+// WithHealthInterval specify the interval of performing the checks
+// to update grpc health status, default is 5s
+func WithHealthInterval(interval time.Duration) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.healthInterval = interval
+	})
+}
+
+// StartInterceptor derives the ctx of Application before the servers start
+//
+// Deprecated: use Hook or Application.OnStart instead
+type StartInterceptor func(context.Context) (context.Context, error)
+
+// WithBeforeStart append a start hook which derives the ctx of Application,
+// the returned ctx must be derived from the given ctx
+//
+// Deprecated: use WithHooks or Application.OnStart instead
+func WithBeforeStart(handler StartInterceptor) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.lifecycle.append(Hook{Name: "before start", intercept: handler})
+	})
+}
+
+// OnStartup append a start hook which is called with the Application
+//
+// Deprecated: use WithHooks or Application.OnStart instead
 func OnStartup(fn func(app *Application) error) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.lifecycle.append(Hook{Name: "startup", OnStart: func(context.Context) error {
+			return fn(app)
+		}})
+	})
+}
+
+// WithHooks append the hooks to Application lifecycle
+func WithHooks(hooks ...Hook) utility.Option[Application] {
+	return utility.OptionFunc[Application](func(app *Application) {
+		app.lifecycle.append(hooks...)
+	})
+}
+
+func WithHttpOpts(hopts ...utility.Option[httpx.Server]) utility.Option[Application] {
 	return utility.OptionFunc[Application](func(cfg *Application) {
-		cfg.onStartup = fn
+		cfg.hopts = append(cfg.hopts, hopts...)
 	})
 }
 
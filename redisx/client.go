@@ -0,0 +1,67 @@
+package redisx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/charliego3/logger"
+	"github.com/charliego3/pallas/configx"
+	"github.com/redis/go-redis/v9"
+)
+
+// New returns redis client from config, it's a cluster client if
+// multiple addresses, a failover client if MasterName specified,
+// otherwise a single node client
+func New(cfg configx.Redis) redis.UniversalClient {
+	return redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs:            cfg.Address,
+		DB:               cfg.DB,
+		Username:         cfg.Username,
+		Password:         cfg.Password,
+		SentinelUsername: cfg.SentinelUsername,
+		SentinelPassword: cfg.SentinelPassword,
+		MaxRetries:       cfg.MaxRetries,
+		MinRetryBackoff:  cfg.MinRetryBackoff,
+		MaxRetryBackoff:  cfg.MaxRetryBackoff,
+		DialTimeout:      cfg.DialTimeout,
+		ReadTimeout:      cfg.ReadTimeout,
+		WriteTimeout:     cfg.WriteTimeout,
+		PoolFIFO:         cfg.PoolFIFO,
+		PoolSize:         cfg.PoolSize,
+		PoolTimeout:      cfg.PoolTimeout,
+		MinIdleConns:     cfg.MinIdleConns,
+		MaxIdleConns:     cfg.MaxIdleConns,
+		ConnMaxIdleTime:  cfg.ConnMaxIdleTime,
+		ConnMaxLifetime:  cfg.ConnMaxLifetime,
+		MaxRedirects:     cfg.MaxRedirects,
+		ReadOnly:         cfg.ReadOnly,
+		RouteByLatency:   cfg.RouteByLatency,
+		RouteRandomly:    cfg.RouteRandomly,
+		MasterName:       cfg.MasterName,
+	})
+}
+
+var (
+	c redis.UniversalClient
+	s sync.Once
+)
+
+// C returns redis client from config
+func C() redis.UniversalClient {
+	s.Do(func() {
+		cfg, err := configx.Fetch[configx.Redis]()
+		if err != nil {
+			logger.Fatal("not resolved redis config", "err", err)
+		}
+
+		c = New(cfg)
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := c.Ping(ctx).Err(); err != nil {
+			logger.Fatal("connect redis server fail", "err", err)
+		}
+		logger.Info("connected redis", "address", cfg.Address)
+	})
+	return c
+}
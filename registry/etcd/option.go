@@ -0,0 +1,40 @@
+package etcd
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/charliego3/pallas/utility"
+)
+
+type options struct {
+	// namespace is the key prefix of all service instances
+	namespace string
+
+	// ttl is the lease time-to-live of an instance,
+	// the instance will be removed if keepalive lost more than ttl
+	ttl time.Duration
+
+	logger *slog.Logger
+}
+
+// WithNamespace specify the key prefix, default is /pallas/services
+func WithNamespace(namespace string) utility.Option[Registry] {
+	return utility.OptionFunc[Registry](func(r *Registry) {
+		r.namespace = namespace
+	})
+}
+
+// WithTTL specify the lease time-to-live, default is 15s,
+// it is rounded up to seconds since etcd lease ttl is in seconds
+func WithTTL(ttl time.Duration) utility.Option[Registry] {
+	return utility.OptionFunc[Registry](func(r *Registry) {
+		r.ttl = ttl
+	})
+}
+
+func WithLogger(logger *slog.Logger) utility.Option[Registry] {
+	return utility.OptionFunc[Registry](func(r *Registry) {
+		r.logger = logger
+	})
+}
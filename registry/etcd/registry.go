@@ -0,0 +1,224 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charliego3/pallas/etcdx"
+	"github.com/charliego3/pallas/registry"
+	"github.com/charliego3/pallas/utility"
+	ev3 "go.etcd.io/etcd/client/v3"
+)
+
+var (
+	_ registry.Registry  = (*Registry)(nil)
+	_ registry.Discovery = (*Registry)(nil)
+)
+
+// Registry is an etcd registry, every instance is written
+// under a lease and kept alive until UnRegister
+type Registry struct {
+	*options
+	client *etcdx.Client
+
+	mu         sync.Mutex
+	heartbeats map[string]*lease
+}
+
+// lease is the current lease of a registered instance,
+// the id is replaced once it's registered again
+type lease struct {
+	id     atomic.Int64
+	cancel context.CancelFunc
+}
+
+// New returns etcd Registry using the client
+func New(client *etcdx.Client, opts ...utility.Option[Registry]) *Registry {
+	r := new(Registry)
+	r.client = client
+	r.heartbeats = make(map[string]*lease)
+	r.options = &options{
+		namespace: "/pallas/services",
+		ttl:       time.Second * 15,
+		logger:    slog.Default(),
+	}
+	utility.Apply(r, opts...)
+	return r
+}
+
+// Register the service instance with a lease and keep it alive in background,
+// the instance will be registered again when the lease is lost
+func (r *Registry) Register(ctx context.Context, ins *registry.ServiceInstance) error {
+	value, err := json.Marshal(ins)
+	if err != nil {
+		return err
+	}
+
+	key := r.key(ins)
+	leaseID, err := r.register(ctx, key, string(value))
+	if err != nil {
+		return err
+	}
+
+	hctx, cancel := context.WithCancel(context.Background())
+	l := &lease{cancel: cancel}
+	l.id.Store(int64(leaseID))
+	r.mu.Lock()
+	if prev, ok := r.heartbeats[key]; ok {
+		prev.cancel()
+	}
+	r.heartbeats[key] = l
+	r.mu.Unlock()
+
+	go r.heartbeat(hctx, l, key, string(value))
+	return nil
+}
+
+// UnRegister stop keepalive, revoke the lease and remove the service instance
+func (r *Registry) UnRegister(ctx context.Context, ins *registry.ServiceInstance) error {
+	key := r.key(ins)
+	r.mu.Lock()
+	l, ok := r.heartbeats[key]
+	delete(r.heartbeats, key)
+	r.mu.Unlock()
+
+	if ok {
+		l.cancel()
+		if id := ev3.LeaseID(l.id.Load()); id != 0 {
+			if _, err := r.client.Revoke(ctx, id); err != nil {
+				r.logger.Warn("[Registry] revoke lease failed",
+					slog.String("key", key), slog.Any("err", err))
+			}
+		}
+	}
+	_, err := r.client.Delete(ctx, key)
+	return err
+}
+
+// GetService returns all instances of the service name
+func (r *Registry) GetService(ctx context.Context, name string) ([]*registry.ServiceInstance, error) {
+	resp, err := r.client.Get(ctx, r.prefix(name), ev3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]*registry.ServiceInstance, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		ins := new(registry.ServiceInstance)
+		if err := json.Unmarshal(kv.Value, ins); err != nil {
+			r.logger.Warn("[Registry] invalid instance",
+				slog.String("key", string(kv.Key)), slog.Any("err", err))
+			continue
+		}
+		instances = append(instances, ins)
+	}
+	return instances, nil
+}
+
+// Watch returns a watcher which notified when the service instances changed
+func (r *Registry) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	return newWatcher(ctx, r, name), nil
+}
+
+// register grant a lease and put the key with the lease,
+// the lease is revoked if the key failed to put
+func (r *Registry) register(ctx context.Context, key, value string) (ev3.LeaseID, error) {
+	grant, err := r.client.Grant(ctx, r.leaseTTL())
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = r.client.Put(ctx, key, value, ev3.WithLease(grant.ID))
+	if err != nil {
+		rctx, cancel := context.WithTimeout(context.Background(), r.ttl)
+		defer cancel()
+		if _, rerr := r.client.Revoke(rctx, grant.ID); rerr != nil {
+			r.logger.Warn("[Registry] revoke lease failed",
+				slog.String("key", key), slog.Any("err", rerr))
+		}
+		return 0, err
+	}
+	return grant.ID, nil
+}
+
+// leaseTTL returns the ttl in seconds, the fraction is rounded up
+// so that the ttl less than 1s won't be 0
+func (r *Registry) leaseTTL() int64 {
+	return max(int64(math.Ceil(r.ttl.Seconds())), 1)
+}
+
+// heartbeat keep the lease alive until ctx done,
+// the key will be registered again with backoff after lease lost
+func (r *Registry) heartbeat(ctx context.Context, l *lease, key, value string) {
+	leaseID := ev3.LeaseID(l.id.Load())
+	kac, err := r.client.KeepAlive(ctx, leaseID)
+	if err != nil {
+		leaseID = 0
+	}
+
+	for {
+		if leaseID == 0 {
+			for attempt := 0; ; attempt++ {
+				if ctx.Err() != nil {
+					return
+				}
+
+				tctx, cancel := context.WithTimeout(ctx, r.ttl)
+				leaseID, err = r.register(tctx, key, value)
+				if err == nil {
+					kac, err = r.client.KeepAlive(ctx, leaseID)
+				}
+				cancel()
+				if err == nil {
+					l.id.Store(int64(leaseID))
+					r.logger.Info("[Registry] re-registered", slog.String("key", key))
+					break
+				}
+
+				leaseID = 0
+				r.logger.Warn("[Registry] re-register failed",
+					slog.String("key", key), slog.Any("err", err))
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(r.backoff(attempt)):
+				}
+			}
+		}
+
+		select {
+		case _, ok := <-kac:
+			if !ok {
+				if ctx.Err() != nil {
+					return
+				}
+				r.logger.Warn("[Registry] keepalive lost", slog.String("key", key))
+				leaseID = 0
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// backoff returns the exponential waiting duration limited by ttl
+func (r *Registry) backoff(attempt int) time.Duration {
+	d := time.Millisecond * 500 << min(attempt, 10)
+	return min(d, r.ttl)
+}
+
+func (r *Registry) key(ins *registry.ServiceInstance) string {
+	return path.Join(r.namespace, ins.Name, ins.ID)
+}
+
+// prefix returns the key prefix of service name with trailing slash,
+// so that service "user" won't match "user-admin"
+func (r *Registry) prefix(name string) string {
+	return path.Join(r.namespace, name) + "/"
+}
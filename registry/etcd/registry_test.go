@@ -0,0 +1,181 @@
+package etcd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charliego3/pallas/etcdx"
+	"github.com/charliego3/pallas/registry"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	ev3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeEtcd implements the KV and Lease used by Registry in memory
+type fakeEtcd struct {
+	ev3.KV
+	ev3.Lease
+
+	mu       sync.Mutex
+	nextID   ev3.LeaseID
+	ttls     []int64
+	revoked  []ev3.LeaseID
+	putErr   error
+	kvs      map[string]ev3.LeaseID
+	values   map[string]string
+	keepers  map[ev3.LeaseID]chan *ev3.LeaseKeepAliveResponse
+	keepSeen chan ev3.LeaseID
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{
+		kvs:      make(map[string]ev3.LeaseID),
+		values:   make(map[string]string),
+		keepers:  make(map[ev3.LeaseID]chan *ev3.LeaseKeepAliveResponse),
+		keepSeen: make(chan ev3.LeaseID, 10),
+	}
+}
+
+func (f *fakeEtcd) Grant(_ context.Context, ttl int64) (*ev3.LeaseGrantResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	f.ttls = append(f.ttls, ttl)
+	return &ev3.LeaseGrantResponse{ID: f.nextID, TTL: ttl}, nil
+}
+
+func (f *fakeEtcd) Revoke(_ context.Context, id ev3.LeaseID) (*ev3.LeaseRevokeResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.revoked = append(f.revoked, id)
+	return &ev3.LeaseRevokeResponse{}, nil
+}
+
+func (f *fakeEtcd) KeepAlive(ctx context.Context, id ev3.LeaseID) (<-chan *ev3.LeaseKeepAliveResponse, error) {
+	ch := make(chan *ev3.LeaseKeepAliveResponse)
+	f.mu.Lock()
+	f.keepers[id] = ch
+	f.mu.Unlock()
+	f.keepSeen <- id
+	return ch, nil
+}
+
+// lose closes the keepalive channel of the lease and removes its keys
+func (f *fakeEtcd) lose(id ev3.LeaseID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for key, lease := range f.kvs {
+		if lease == id {
+			delete(f.kvs, key)
+			delete(f.values, key)
+		}
+	}
+	close(f.keepers[id])
+}
+
+func (f *fakeEtcd) Put(_ context.Context, key, value string, opts ...ev3.OpOption) (*ev3.PutResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.putErr != nil {
+		return nil, f.putErr
+	}
+	f.kvs[key] = f.nextID
+	f.values[key] = value
+	return &ev3.PutResponse{}, nil
+}
+
+func (f *fakeEtcd) Get(_ context.Context, key string, _ ...ev3.OpOption) (*ev3.GetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := new(ev3.GetResponse)
+	for k, v := range f.values {
+		if len(k) >= len(key) && k[:len(key)] == key {
+			resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(k), Value: []byte(v)})
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeEtcd) Delete(_ context.Context, key string, _ ...ev3.OpOption) (*ev3.DeleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.kvs, key)
+	delete(f.values, key)
+	return &ev3.DeleteResponse{}, nil
+}
+
+func newTestRegistry(f *fakeEtcd) *Registry {
+	client := &etcdx.Client{Client: &ev3.Client{KV: f, Lease: f}}
+	return New(client, WithTTL(300*time.Millisecond))
+}
+
+func TestRegisterPutFailed(t *testing.T) {
+	f := newFakeEtcd()
+	f.putErr = errors.New("put failed")
+	r := newTestRegistry(f)
+
+	err := r.Register(context.Background(), &registry.ServiceInstance{ID: "1", Name: "user"})
+	if !errors.Is(err, f.putErr) {
+		t.Fatalf("Register = %v, want put failed", err)
+	}
+	if len(f.revoked) != 1 || f.revoked[0] != 1 {
+		t.Fatalf("revoked = %v, want the granted lease", f.revoked)
+	}
+	// the ttl less than 1s is rounded up
+	if f.ttls[0] != 1 {
+		t.Fatalf("ttl = %d, want 1", f.ttls[0])
+	}
+}
+
+func TestReRegister(t *testing.T) {
+	f := newFakeEtcd()
+	r := newTestRegistry(f)
+	ins := &registry.ServiceInstance{ID: "1", Name: "user"}
+	ctx := context.Background()
+	if err := r.Register(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+
+	wait := func() ev3.LeaseID {
+		t.Helper()
+		select {
+		case id := <-f.keepSeen:
+			return id
+		case <-time.After(time.Second):
+			t.Fatal("keepalive is not started")
+			return 0
+		}
+	}
+	first := wait()
+
+	// the instance is registered again with a new lease after the keepalive lost
+	f.lose(first)
+	second := wait()
+	if second == first {
+		t.Fatalf("lease = %d, want a new lease", second)
+	}
+	instances, err := r.GetService(ctx, "user")
+	if err != nil || len(instances) != 1 || instances[0].ID != "1" {
+		t.Fatalf("GetService = %v, %v", instances, err)
+	}
+
+	if err := r.UnRegister(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+	if instances, _ := r.GetService(ctx, "user"); len(instances) != 0 {
+		t.Fatalf("instances = %v after UnRegister", instances)
+	}
+	// the current lease is revoked rather than left until expired
+	f.mu.Lock()
+	revoked := f.revoked
+	f.mu.Unlock()
+	if len(revoked) != 1 || revoked[0] != second {
+		t.Fatalf("revoked = %v, want the lease %d", revoked, second)
+	}
+	// unknown instance is only deleted
+	if err := r.UnRegister(ctx, &registry.ServiceInstance{ID: "2", Name: "user"}); err != nil {
+		t.Fatal(err)
+	}
+}
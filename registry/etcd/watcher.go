@@ -0,0 +1,54 @@
+package etcd
+
+import (
+	"context"
+
+	"github.com/charliego3/pallas/registry"
+	ev3 "go.etcd.io/etcd/client/v3"
+)
+
+var _ registry.Watcher = (*watcher)(nil)
+
+type watcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	r      *Registry
+	name   string
+	first  bool
+	events ev3.WatchChan
+}
+
+func newWatcher(ctx context.Context, r *Registry, name string) *watcher {
+	w := &watcher{r: r, name: name, first: true}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.events = r.client.Watch(w.ctx, r.prefix(name), ev3.WithPrefix())
+	return w
+}
+
+// Next returns the current instances at first time,
+// then blocks until any instance changed
+func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
+	if w.first {
+		w.first = false
+		return w.r.GetService(w.ctx, w.name)
+	}
+
+	select {
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	case resp, ok := <-w.events:
+		if !ok {
+			return nil, context.Canceled
+		}
+		if err := resp.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return w.r.GetService(w.ctx, w.name)
+}
+
+// Stop the watcher and release the watch channel
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
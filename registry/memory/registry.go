@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/charliego3/pallas/registry"
+)
+
+var (
+	_ registry.Registry  = (*Registry)(nil)
+	_ registry.Discovery = (*Registry)(nil)
+)
+
+// Registry is an in-process registry, it's useful for tests
+// and the services run in the same process
+type Registry struct {
+	mu       sync.RWMutex
+	services map[string]map[string]*registry.ServiceInstance
+	watchers map[string]map[*watcher]struct{}
+}
+
+// New returns an empty in-memory Registry
+func New() *Registry {
+	return &Registry{
+		services: make(map[string]map[string]*registry.ServiceInstance),
+		watchers: make(map[string]map[*watcher]struct{}),
+	}
+}
+
+// Register add or replace the service instance with the same id
+func (r *Registry) Register(_ context.Context, ins *registry.ServiceInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	instances, ok := r.services[ins.Name]
+	if !ok {
+		instances = make(map[string]*registry.ServiceInstance)
+		r.services[ins.Name] = instances
+	}
+	instances[ins.ID] = ins
+	r.notify(ins.Name)
+	return nil
+}
+
+// UnRegister remove the service instance
+func (r *Registry) UnRegister(_ context.Context, ins *registry.ServiceInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if instances, ok := r.services[ins.Name]; ok {
+		delete(instances, ins.ID)
+		if len(instances) == 0 {
+			delete(r.services, ins.Name)
+		}
+	}
+	r.notify(ins.Name)
+	return nil
+}
+
+// GetService returns all instances of the service name
+func (r *Registry) GetService(_ context.Context, name string) ([]*registry.ServiceInstance, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	instances := make([]*registry.ServiceInstance, 0, len(r.services[name]))
+	for _, ins := range r.services[name] {
+		instances = append(instances, ins)
+	}
+	return instances, nil
+}
+
+// Watch returns a watcher which notified when the service instances changed
+func (r *Registry) Watch(ctx context.Context, name string) (registry.Watcher, error) {
+	w := &watcher{r: r, name: name, first: true, event: make(chan struct{}, 1)}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	r.mu.Lock()
+	if _, ok := r.watchers[name]; !ok {
+		r.watchers[name] = make(map[*watcher]struct{})
+	}
+	r.watchers[name][w] = struct{}{}
+	r.mu.Unlock()
+	return w, nil
+}
+
+// notify all watchers of the name, must be called with lock held
+func (r *Registry) notify(name string) {
+	for w := range r.watchers[name] {
+		select {
+		case w.event <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (r *Registry) removeWatcher(w *watcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.watchers[w.name], w)
+	if len(r.watchers[w.name]) == 0 {
+		delete(r.watchers, w.name)
+	}
+}
+
+type watcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	r      *Registry
+	name   string
+	first  bool
+	event  chan struct{}
+}
+
+// Next returns the current instances at first time,
+// then blocks until any instance changed
+func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
+	if w.first {
+		w.first = false
+		return w.r.GetService(w.ctx, w.name)
+	}
+
+	select {
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	case <-w.event:
+	}
+	return w.r.GetService(w.ctx, w.name)
+}
+
+// Stop the watcher
+func (w *watcher) Stop() error {
+	w.cancel()
+	w.r.removeWatcher(w)
+	return nil
+}
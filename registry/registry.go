@@ -2,10 +2,70 @@ package registry
 
 import (
 	"context"
-	"google.golang.org/grpc"
+	"net/url"
 )
 
+// ServiceInstance is an instance of a service in the registry
+type ServiceInstance struct {
+	// ID is the unique instance ID as registered
+	ID string `json:"id"`
+
+	// Name is the service name as registered
+	Name string `json:"name"`
+
+	// Version is the version of the compiled
+	Version string `json:"version"`
+
+	// Metadata is the kv pair metadata associated with the service instance
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Endpoints are endpoint addresses of the service instance
+	// schema:
+	//   http://127.0.0.1:8000
+	//   grpc://127.0.0.1:9000
+	Endpoints []string `json:"endpoints"`
+}
+
+// Endpoint returns the first endpoint host that matches the scheme
+func (s *ServiceInstance) Endpoint(scheme string) (string, bool) {
+	for _, e := range s.Endpoints {
+		u, err := url.Parse(e)
+		if err != nil {
+			continue
+		}
+		if u.Scheme == scheme {
+			return u.Host, true
+		}
+	}
+	return "", false
+}
+
+// Registry is service registrar
 type Registry interface {
-	Register(context.Context, grpc.ServiceInfo)
-	UnRegister()
+	// Register the service instance to registry
+	Register(context.Context, *ServiceInstance) error
+
+	// UnRegister remove the service instance from registry
+	UnRegister(context.Context, *ServiceInstance) error
+}
+
+// Discovery is service discovery
+type Discovery interface {
+	// GetService return the service instances in memory according to the service name
+	GetService(ctx context.Context, name string) ([]*ServiceInstance, error)
+
+	// Watch creates a watcher according to the service name
+	Watch(ctx context.Context, name string) (Watcher, error)
+}
+
+// Watcher is service watcher
+type Watcher interface {
+	// Next returns services in the following two cases:
+	// 1. the first time to watch
+	// 2. any service instance changes found
+	// if the above two conditions are not met, it will block until context deadline exceeded or canceled
+	Next() ([]*ServiceInstance, error)
+
+	// Stop close the watcher
+	Stop() error
 }
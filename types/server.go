@@ -38,7 +38,10 @@ type GrpcServiceDesc = grpc.ServiceDesc
 type HttpMethodDesc struct {
 	Method   string
 	Template string
-	Handler  func(Service) any
+
+	// Operation is the full grpc method, eg: /pb.User/Login
+	Operation string
+	Handler   func(Service) any
 }
 
 type HttpServiceDesc struct {
@@ -55,3 +58,11 @@ type ServiceDesc struct {
 type Service interface {
 	Desc() ServiceDesc
 }
+
+// Component is a managed background task, eg: consumer, ticker and cache warmer.
+// Start blocks until ctx is done, Stop is called or a failure occurred,
+// returns nil means the component is finished
+type Component interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
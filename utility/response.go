@@ -13,9 +13,11 @@ type ResponseEntity interface {
 }
 
 type re struct {
-	Codes   int    `json:"code"`
-	Msg     string `json:"message"`
-	Payload any    `json:"payload,omitempty"`
+	Codes    int               `json:"code"`
+	Msg      string            `json:"message"`
+	Reason   string            `json:"reason,omitempty" xml:"reason,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty" xml:"-"`
+	Payload  any               `json:"payload,omitempty"`
 }
 
 func (r re) Code() int {
@@ -38,6 +40,12 @@ func NewErrResponse(code int, msg string) ResponseEntity {
 	return &re{Codes: code, Msg: msg}
 }
 
+// NewReasonResponse returns the error response with the reason
+// and metadata of the error, the metadata is omitted in xml
+func NewReasonResponse(code int, reason, msg string, metadata map[string]string) ResponseEntity {
+	return &re{Codes: code, Msg: msg, Reason: reason, Metadata: metadata}
+}
+
 func NewResponse(payload any) ResponseEntity {
 	return &re{Codes: http.StatusOK, Msg: "OK", Payload: payload}
 }